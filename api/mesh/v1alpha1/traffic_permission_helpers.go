@@ -0,0 +1,5 @@
+package v1alpha1
+
+// MatchAllTag is the special tag value a Source's Match uses to mean "any
+// value for this tag", rather than matching no value at all.
+const MatchAllTag = "*"
@@ -0,0 +1,47 @@
+package api_server
+
+// Config holds cross-cutting HTTP concerns for the API server that are not
+// specific to any single resourceWs, such as CORS and response compression.
+type Config struct {
+	Cors        CorsConfig
+	Compression CompressionConfig
+}
+
+// CorsConfig configures the go-restful CrossOriginResourceSharing filter so
+// that browser-based dashboards hosted on a different origin can call the API
+// server.
+type CorsConfig struct {
+	// AllowedOrigins lists the origins that may make cross-origin requests.
+	// An empty list disables CORS entirely.
+	AllowedOrigins []string
+	// AllowedHeaders lists the request headers a client is allowed to send.
+	AllowedHeaders []string
+	// AllowedMethods lists the HTTP methods a client is allowed to use.
+	AllowedMethods []string
+}
+
+// CompressionConfig configures gzip compression of API responses.
+type CompressionConfig struct {
+	// Enabled turns on gzip compression for clients that send
+	// Accept-Encoding: gzip. Deflate is not offered; clients that accept only
+	// deflate get uncompressed responses.
+	Enabled bool
+	// MinBytes is the smallest response body, in bytes, worth compressing.
+	// Responses smaller than this are written uncompressed to avoid the
+	// overhead of compressing tiny payloads.
+	MinBytes int
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Cors: CorsConfig{
+			AllowedOrigins: []string{"*"},
+			AllowedHeaders: []string{"Content-Type", "Accept"},
+			AllowedMethods: []string{"GET", "POST", "PUT", "DELETE"},
+		},
+		Compression: CompressionConfig{
+			Enabled:  true,
+			MinBytes: 1024,
+		},
+	}
+}
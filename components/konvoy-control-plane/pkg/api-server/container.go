@@ -0,0 +1,110 @@
+package api_server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strings"
+
+	"github.com/emicklei/go-restful"
+)
+
+// NewContainer builds a go-restful Container with the cross-cutting filters
+// (currently just CORS) configured, ready to have the individual resourceWs
+// (and meshWs) WebServices added to it. Compression is NOT wired here: it is
+// applied per-route by resourceWs.NewWs via compressionFilter, because a
+// container-wide filter would also buffer the long-lived watch=true stream
+// (see compressionFilter's doc comment).
+func NewContainer(cfg Config) *restful.Container {
+	container := restful.NewContainer()
+
+	if len(cfg.Cors.AllowedOrigins) > 0 {
+		cors := restful.CrossOriginResourceSharing{
+			AllowedDomains: cfg.Cors.AllowedOrigins,
+			AllowedHeaders: cfg.Cors.AllowedHeaders,
+			AllowedMethods: cfg.Cors.AllowedMethods,
+			CookiesAllowed: false,
+			Container:      container,
+		}
+		container.Filter(cors.Filter)
+	}
+
+	return container
+}
+
+// compressionFilter gzip-encodes the response body when the client sent
+// Accept-Encoding: gzip and the body is at least minBytes long; smaller
+// bodies are written through unchanged, since gzip's framing overhead
+// dominates for tiny payloads. go-restful's own EnableContentEncoding wraps
+// every response writer before a single byte of the body is written, so it
+// has no way to honor MinBytes; this buffers the body instead so it can be
+// measured before deciding whether compressing it is worthwhile.
+//
+// It never buffers a watch=true request: resource_ws.go registers it
+// per-route rather than container-wide specifically so it only ever sees
+// routes it's safe to apply to, but listOrWatchResources's single route
+// serves both the snapshot list and the long-lived watch stream, and
+// buffering the latter would hold every event (and the 200 status itself)
+// behind bufferingResponseWriter until the watch ends, and would also hide
+// the real http.Flusher that resource_watch_ws.go needs to flush each event.
+func compressionFilter(minBytes int) restful.FilterFunction {
+	return func(request *restful.Request, response *restful.Response, chain *restful.FilterChain) {
+		if request.QueryParameter("watch") == "true" {
+			chain.ProcessFilter(request, response)
+			return
+		}
+		if !strings.Contains(request.Request.Header.Get("Accept-Encoding"), "gzip") {
+			chain.ProcessFilter(request, response)
+			return
+		}
+
+		buffered := &bufferingResponseWriter{ResponseWriter: response.ResponseWriter, body: &bytes.Buffer{}}
+		response.ResponseWriter = buffered
+
+		chain.ProcessFilter(request, response)
+
+		response.ResponseWriter = buffered.ResponseWriter
+		if buffered.body.Len() < minBytes {
+			buffered.flush()
+		} else {
+			buffered.flushGzipped()
+		}
+	}
+}
+
+// bufferingResponseWriter buffers a handler's response so compressionFilter
+// can decide, once the full body size is known, whether to compress it.
+type bufferingResponseWriter struct {
+	http.ResponseWriter
+	body       *bytes.Buffer
+	statusCode int
+}
+
+func (w *bufferingResponseWriter) WriteHeader(statusCode int) {
+	w.statusCode = statusCode
+}
+
+func (w *bufferingResponseWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+func (w *bufferingResponseWriter) writeStatus() {
+	if w.statusCode != 0 {
+		w.ResponseWriter.WriteHeader(w.statusCode)
+	}
+}
+
+func (w *bufferingResponseWriter) flush() {
+	w.writeStatus()
+	_, _ = w.ResponseWriter.Write(w.body.Bytes())
+}
+
+func (w *bufferingResponseWriter) flushGzipped() {
+	w.Header().Set("Content-Encoding", "gzip")
+	w.Header().Del("Content-Length")
+	w.writeStatus()
+
+	gzipWriter := gzip.NewWriter(w.ResponseWriter)
+	_, _ = gzipWriter.Write(w.body.Bytes())
+	_ = gzipWriter.Close()
+}
@@ -0,0 +1,153 @@
+package api_server
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mesh_core "github.com/Kong/konvoy/components/konvoy-control-plane/pkg/core/resources/apis/mesh"
+	"github.com/Kong/konvoy/components/konvoy-control-plane/pkg/core/resources/model"
+	"github.com/Kong/konvoy/components/konvoy-control-plane/pkg/core/resources/store"
+	"github.com/emicklei/go-restful"
+)
+
+func newCompressionTestContainer(t *testing.T, minBytes int, body string) *restful.Container {
+	t.Helper()
+	container := NewContainer(Config{Compression: CompressionConfig{Enabled: true, MinBytes: minBytes}})
+
+	ws := new(restful.WebService)
+	// NewContainer doesn't wire compression itself, so register
+	// compressionFilter per-route the same way resourceWs.withCompression does.
+	ws.Route(ws.GET("/body").Filter(compressionFilter(minBytes)).To(func(request *restful.Request, response *restful.Response) {
+		_, _ = response.Write([]byte(body))
+	}))
+	container.Add(ws)
+	return container
+}
+
+func doGzipRequest(container *restful.Container) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/body", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	recorder := httptest.NewRecorder()
+	container.ServeHTTP(recorder, req)
+	return recorder
+}
+
+// A response shorter than MinBytes must be written uncompressed: gzip's
+// framing overhead would make a tiny payload bigger, not smaller.
+func TestCompressionFilterSkipsBodiesBelowMinBytes(t *testing.T) {
+	container := newCompressionTestContainer(t, 1024, "short")
+	recorder := doGzipRequest(container)
+
+	if recorder.Header().Get("Content-Encoding") == "gzip" {
+		t.Fatalf("expected an uncompressed response for a body under MinBytes")
+	}
+	if recorder.Body.String() != "short" {
+		t.Fatalf("expected body %q, got %q", "short", recorder.Body.String())
+	}
+}
+
+// A response at or above MinBytes must be gzip-encoded when the client asks
+// for it.
+func TestCompressionFilterCompressesBodiesAtOrAboveMinBytes(t *testing.T) {
+	body := strings.Repeat("a", 2048)
+	container := newCompressionTestContainer(t, 1024, body)
+	recorder := doGzipRequest(container)
+
+	if recorder.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("expected a gzip-encoded response for a body over MinBytes")
+	}
+	if recorder.Body.String() == body {
+		t.Fatalf("expected the recorded body to be gzip-compressed, got it verbatim")
+	}
+}
+
+type containerTestResourceList struct {
+	items []model.Resource
+}
+
+func (l *containerTestResourceList) GetItemType() model.ResourceType { return "TestType" }
+func (l *containerTestResourceList) GetItems() []model.Resource      { return l.items }
+func (l *containerTestResourceList) NewItem() model.Resource         { return newTestResource() }
+func (l *containerTestResourceList) AddItem(r model.Resource) error {
+	l.items = append(l.items, r)
+	return nil
+}
+
+// TestWatchEndpointIsNotBufferedWhenCompressionIsEnabled proves the fix for
+// the compression filter swallowing the watch=true stream: even with
+// Accept-Encoding: gzip and compression enabled on the container, a watch
+// event must reach the client as soon as it happens rather than sitting
+// behind bufferingResponseWriter until the handler returns watchTimeout
+// later. It drives a real connection through httptest.NewServer (not
+// ServeHTTP against a ResponseRecorder) because the point being tested is
+// exactly the thing a ResponseRecorder can't observe: bytes arriving before
+// the handler finishes.
+func TestWatchEndpointIsNotBufferedWhenCompressionIsEnabled(t *testing.T) {
+	resourceStore := store.NewMemoryResourceStore()
+	r := &resourceWs{
+		resourceStore: resourceStore,
+		global:        true,
+		compression:   CompressionConfig{Enabled: true, MinBytes: 1},
+		ResourceWsDefinition: ResourceWsDefinition{
+			Name:                "Test",
+			Path:                "tests",
+			ResourceFactory:     newTestResource,
+			ResourceListFactory: func() model.ResourceList { return &containerTestResourceList{} },
+		},
+	}
+
+	container := restful.NewContainer()
+	container.Add(r.NewWs())
+
+	server := httptest.NewServer(container)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/tests?watch=true", nil)
+	if err != nil {
+		t.Fatalf("building request failed: %v", err)
+	}
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	// Do only returns once headers are received, which watchResources sends
+	// after it has already registered the Watch, so the Create below is
+	// guaranteed to land on an active watcher rather than racing it.
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("watch request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		t.Fatalf("expected the watch stream to bypass compression, got Content-Encoding: gzip")
+	}
+
+	resource := newTestResource()
+	// A global resourceWs watches mesh_core.GlobalNamespace, so the resource
+	// must be created there for the watch to observe it.
+	resource.SetMeta(&testMeta{name: "one", namespace: mesh_core.GlobalNamespace})
+	if err := resourceStore.Create(context.Background(), resource, store.CreateByName(mesh_core.GlobalNamespace, "one")); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	line := make(chan string, 1)
+	go func() {
+		l, _ := bufio.NewReader(resp.Body).ReadString('\n')
+		line <- l
+	}()
+
+	select {
+	case l := <-line:
+		if !strings.Contains(l, `"name":"one"`) {
+			t.Fatalf("expected the watch event for resource %q on the live connection, got %q", "one", l)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the watch event to arrive on the live connection")
+	}
+}
@@ -0,0 +1,26 @@
+package api_server
+
+import (
+	mesh_core "github.com/Kong/konvoy/components/konvoy-control-plane/pkg/core/resources/apis/mesh"
+	"github.com/Kong/konvoy/components/konvoy-control-plane/pkg/core/resources/store"
+)
+
+// newMeshWs exposes a top-level CRUD API for the Mesh resource itself, as
+// opposed to resourceWs instances serving resources scoped to an
+// already-existing Mesh. It is just a resourceWs with global set, so it gets
+// the same Get/List/Create/Update/Delete handling (including the
+// resourceVersion conflict check and watch support) without forking the
+// logic.
+func newMeshWs(resourceStore store.ResourceStore, compression CompressionConfig) *resourceWs {
+	return &resourceWs{
+		resourceStore: resourceStore,
+		global:        true,
+		compression:   compression,
+		ResourceWsDefinition: ResourceWsDefinition{
+			Name:                "Mesh",
+			Path:                "meshes",
+			ResourceFactory:     mesh_core.NewMeshResource,
+			ResourceListFactory: mesh_core.NewMeshResourceList,
+		},
+	}
+}
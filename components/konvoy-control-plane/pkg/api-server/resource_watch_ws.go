@@ -0,0 +1,97 @@
+package api_server
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/Kong/konvoy/components/konvoy-control-plane/pkg/core/resources/store"
+	"github.com/emicklei/go-restful"
+	"sigs.k8s.io/controller-runtime/pkg/log" // todo(jakubdyszkiewicz) replace with core
+)
+
+// watchTimeout bounds how long a single watch connection is kept open before
+// the client is asked to reconnect (and, if needed, re-list). This mirrors the
+// deadline/cancel pattern used for gonet connections: a timer closes a "done"
+// channel, and the select below ends the stream on that channel or on the
+// request context being done (client disconnect), whichever comes first.
+const watchTimeout = 10 * time.Minute
+
+// watchEvent is the newline-delimited JSON envelope emitted for every change
+// observed on the watched ResourceList.
+type watchEvent struct {
+	Type     string           `json:"type"`
+	Resource *ResourceReqResp `json:"resource"`
+}
+
+// watchResources upgrades a GET .../{path}?watch=true request to a streaming
+// response, emitting one watchEvent per line as the underlying
+// store.ResourceStore changes. It resumes from the resourceVersion query
+// param when present.
+func (r *resourceWs) watchResources(request *restful.Request, response *restful.Response) {
+	meshName := r.namespaceOf(request)
+
+	if !r.global && !r.meshExists(request.Request.Context(), meshName, response) {
+		return
+	}
+
+	list := r.ResourceListFactory()
+	opts := []store.WatchOptionsFunc{store.WatchByNamespace(meshName)}
+	if rv := request.QueryParameter("resourceVersion"); rv != "" {
+		opts = append(opts, store.WatchFromResourceVersion(rv))
+	}
+
+	watcher, err := r.resourceStore.Watch(request.Request.Context(), list, opts...)
+	if err != nil {
+		if err == store.ErrorResourceVersionTooOld {
+			writeError(response, http.StatusGone, "resourceVersion is too old, re-list and watch again")
+		} else {
+			log.Log.Error(err, "Could not start a watch", "mesh", meshName, "type", r.Name)
+			writeError(response, 500, "Could not start a watch")
+		}
+		return
+	}
+	defer watcher.Stop()
+
+	// done is closed when watchTimeout elapses; client disconnect is observed
+	// separately through the request context.
+	done := make(chan struct{})
+	timer := time.AfterFunc(watchTimeout, func() { close(done) })
+	defer timer.Stop()
+
+	response.Header().Set("Content-Type", "application/json")
+	response.WriteHeader(http.StatusOK)
+	encoder := json.NewEncoder(response)
+	flusher, _ := response.ResponseWriter.(http.Flusher)
+	// Send the headers now, once the Watch is registered, rather than with the
+	// first event.
+	if flusher != nil {
+		flusher.Flush()
+	}
+
+	for {
+		select {
+		case <-request.Request.Context().Done():
+			return
+		case <-done:
+			return
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return
+			}
+			res := &ResourceReqResp{
+				Type: string(event.Resource.GetType()),
+				Name: event.Resource.GetMeta().GetName(),
+				Mesh: r.meshFieldOf(meshName),
+				Spec: event.Resource.GetSpec(),
+			}
+			if err := encoder.Encode(watchEvent{Type: string(event.Type), Resource: res}); err != nil {
+				log.Log.Error(err, "Could not write a watch event", "mesh", meshName)
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+	}
+}
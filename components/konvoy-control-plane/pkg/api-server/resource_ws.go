@@ -5,6 +5,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+
+	mesh_core "github.com/Kong/konvoy/components/konvoy-control-plane/pkg/core/resources/apis/mesh"
 	"github.com/Kong/konvoy/components/konvoy-control-plane/pkg/core/resources/model"
 	"github.com/Kong/konvoy/components/konvoy-control-plane/pkg/core/resources/store"
 	"github.com/emicklei/go-restful"
@@ -13,8 +16,6 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log" // todo(jakubdyszkiewicz) replace with core
 )
 
-const namespace = "default"
-
 type ResourceWsDefinition struct {
 	Name                string
 	Path                string
@@ -25,14 +26,25 @@ type ResourceWsDefinition struct {
 type resourceWs struct {
 	resourceStore store.ResourceStore
 	readOnly      bool
+	// global marks a resourceWs that is not scoped under /meshes/{mesh}/... but
+	// lives directly under the top level path (e.g. the Mesh resource itself).
+	// It is stored in mesh_core.GlobalNamespace instead of a {mesh} path param,
+	// and does not need the meshExists precondition since it *is* the Mesh.
+	global bool
+	// compression configures response compression for this WebService's
+	// non-streaming routes. It is applied per-route in NewWs rather than as a
+	// container-wide filter so the watch=true long-lived stream is never
+	// buffered; see compressionFilter.
+	compression CompressionConfig
 	ResourceWsDefinition
 }
 
 type ResourceReqResp struct {
-	Type string             `json:"type"`
-	Name string             `json:"name"`
-	Mesh string             `json:"mesh"`
-	Spec model.ResourceSpec `json:"-"`
+	Type            string             `json:"type"`
+	Name            string             `json:"name"`
+	Mesh            string             `json:"mesh,omitempty"`
+	ResourceVersion string             `json:"resourceVersion,omitempty"`
+	Spec            model.ResourceSpec `json:"-"`
 }
 
 var (
@@ -75,67 +87,116 @@ func (r *ResourceReqResp) UnmarshalJSON(jsonBytes []byte) error {
 	r.Name = values["name"]
 	r.Type = values["type"]
 	r.Mesh = values["mesh"]
+	r.ResourceVersion = values["resourceVersion"]
 	return nil
 }
 
 func (r *resourceWs) NewWs() *restful.WebService {
 	ws := new(restful.WebService)
 
-	ws.
-		Path(fmt.Sprintf("/meshes/{mesh}/%s", r.Path)).
-		Consumes(restful.MIME_JSON).
-		Produces(restful.MIME_JSON).
-		Param(ws.PathParameter("mesh", "Name of the Mesh").DataType("string"))
+	if r.global {
+		ws.
+			Path(fmt.Sprintf("/%s", r.Path)).
+			Consumes(restful.MIME_JSON).
+			Produces(restful.MIME_JSON)
+	} else {
+		ws.
+			Path(fmt.Sprintf("/meshes/{mesh}/%s", r.Path)).
+			Consumes(restful.MIME_JSON).
+			Produces(restful.MIME_JSON).
+			Param(ws.PathParameter("mesh", "Name of the Mesh").DataType("string"))
+	}
 
-	ws.Route(ws.GET("/{name}").To(r.findResource).
+	findRoute := ws.GET("/{name}").To(r.findResource).
 		Doc(fmt.Sprintf("Get a %s", r.Name)).
 		Param(ws.PathParameter("name", fmt.Sprintf("Name of a %s", r.Name)).DataType("string")).
 		//Writes(r.SpecFactory()).
 		Returns(200, "OK", nil). // todo(jakubdyszkiewicz) figure out how to expose the doc for ResourceReqResp
-		Returns(404, "Not found", nil))
+		Returns(404, "Not found", nil)
+	r.withCompression(findRoute)
+	ws.Route(findRoute)
 
-	ws.Route(ws.GET("").To(r.listResources).
-		Doc(fmt.Sprintf("List of %s", r.Name)).
+	// listOrWatchResources dispatches to listResources or the long-lived
+	// watchResources stream at request time, so the same route serves both;
+	// compressionFilter itself skips wrapping whenever watch=true, since
+	// buffering a streaming response would hold every event (and the 200
+	// itself) until the watch ends.
+	listRoute := ws.GET("").To(r.listOrWatchResources).
+		Doc(fmt.Sprintf("List of %s. Pass watch=true to stream changes instead.", r.Name)).
+		Param(ws.QueryParameter("watch", "Stream ADDED/MODIFIED/DELETED events instead of returning a snapshot").DataType("boolean")).
+		Param(ws.QueryParameter("resourceVersion", "Resume a watch from this resourceVersion instead of the current one").DataType("string")).
 		//Writes(r.SampleListSpec).
-		Returns(200, "OK", nil)) // todo(jakubdyszkiewicz) figure out how to expose the doc for ResourceReqResp
+		Returns(200, "OK", nil). // todo(jakubdyszkiewicz) figure out how to expose the doc for ResourceReqResp
+		Returns(410, "Gone, resourceVersion too old, re-list and watch again", nil)
+	r.withCompression(listRoute)
+	ws.Route(listRoute)
 
 	if !r.readOnly {
-		ws.Route(ws.PUT("/{name}").To(r.createOrUpdateResource).
+		putRoute := ws.PUT("/{name}").To(r.createOrUpdateResource).
 			Doc(fmt.Sprintf("Updates a %s", r.Name)).
 			Param(ws.PathParameter("name", fmt.Sprintf("Name of the %s", r.Name)).DataType("string")).
 			//Reads(r.SampleSpec). // todo(jakubdyszkiewicz) figure out how to expose the doc for ResourceReqResp
 			Returns(200, "OK", nil).
-			Returns(201, "Created", nil))
+			Returns(201, "Created", nil)
+		r.withCompression(putRoute)
+		ws.Route(putRoute)
 
-		ws.Route(ws.DELETE("/{name}").To(r.deleteResource).
+		deleteRoute := ws.DELETE("/{name}").To(r.deleteResource).
 			Doc(fmt.Sprintf("Deletes a %s", r.Name)).
 			Param(ws.PathParameter("name", fmt.Sprintf("Name of a %s", r.Name)).DataType("string")).
-			Returns(200, "OK", nil))
+			Returns(200, "OK", nil)
+		r.withCompression(deleteRoute)
+		ws.Route(deleteRoute)
 	}
 
 	return ws
 }
 
+// withCompression registers compressionFilter on rb when this resourceWs has
+// compression enabled. It is applied per-route rather than container-wide so
+// it never touches routes this WebService doesn't know about, and so the
+// watch-awareness inside compressionFilter only has to reason about routes
+// that are actually Compression-configured.
+func (r *resourceWs) withCompression(rb *restful.RouteBuilder) {
+	if r.compression.Enabled {
+		rb.Filter(compressionFilter(r.compression.MinBytes))
+	}
+}
+
+// namespaceOf returns the store namespace a request is scoped to: the Mesh
+// named in the {mesh} path param for ordinary resources, or the fixed global
+// namespace for a resourceWs serving the Mesh resource itself.
+func (r *resourceWs) namespaceOf(request *restful.Request) string {
+	if r.global {
+		return mesh_core.GlobalNamespace
+	}
+	return request.PathParameter("mesh")
+}
+
 func (r *resourceWs) findResource(request *restful.Request, response *restful.Response) {
 	name := request.PathParameter("name")
-	meshName := request.PathParameter("mesh")
+	meshName := r.namespaceOf(request)
+
+	if !r.global && !r.meshExists(request.Request.Context(), meshName, response) {
+		return
+	}
 
-	// todo(jakubdyszkiewicz) find by mesh?
 	resource := r.ResourceFactory()
-	err := r.resourceStore.Get(request.Request.Context(), resource, store.GetByName(namespace, name))
+	err := r.resourceStore.Get(request.Request.Context(), resource, store.GetByName(meshName, name))
 	if err != nil {
-		if err.Error() == store.ErrorResourceNotFound(resource.GetType(), namespace, name).Error() {
+		if err.Error() == store.ErrorResourceNotFound(resource.GetType(), meshName, name).Error() {
 			writeError(response, 404, "")
 		} else {
-			log.Log.Error(err, "Could not retrieve a resource", "name", name)
+			log.Log.Error(err, "Could not retrieve a resource", "mesh", meshName, "name", name)
 			writeError(response, 500, "Could not retrieve a resource")
 		}
 	} else {
 		res := &ResourceReqResp{
-			Type: string(resource.GetType()),
-			Name: name,
-			Mesh: meshName,
-			Spec: resource.GetSpec(),
+			Type:            string(resource.GetType()),
+			Name:            name,
+			Mesh:            r.meshFieldOf(meshName),
+			ResourceVersion: resource.GetMeta().GetVersion(),
+			Spec:            resource.GetSpec(),
 		}
 		err = response.WriteAsJson(res)
 		if err != nil {
@@ -148,22 +209,36 @@ type resourceSpecList struct {
 	Items []*ResourceReqResp `json:"items"`
 }
 
+// listOrWatchResources dispatches between a one-off listResources snapshot and
+// a long-lived watchResources stream, depending on the "watch" query param.
+func (r *resourceWs) listOrWatchResources(request *restful.Request, response *restful.Response) {
+	if request.QueryParameter("watch") == "true" {
+		r.watchResources(request, response)
+		return
+	}
+	r.listResources(request, response)
+}
+
 func (r *resourceWs) listResources(request *restful.Request, response *restful.Response) {
-	meshName := request.PathParameter("mesh")
+	meshName := r.namespaceOf(request)
+
+	if !r.global && !r.meshExists(request.Request.Context(), meshName, response) {
+		return
+	}
 
 	list := r.ResourceListFactory()
-	// todo(jakubdyszkiewicz) find by mesh?
-	if err := r.resourceStore.List(request.Request.Context(), list, store.ListByNamespace(namespace)); err != nil {
-		log.Log.Error(err, "Could not retrieve resources")
+	if err := r.resourceStore.List(request.Request.Context(), list, store.ListByNamespace(meshName)); err != nil {
+		log.Log.Error(err, "Could not retrieve resources", "mesh", meshName)
 		writeError(response, 500, "Could not list a resource")
 	} else {
 		var items []*ResourceReqResp
 		for _, item := range list.GetItems() {
 			items = append(items, &ResourceReqResp{
-				Type: string(item.GetType()),
-				Name: item.GetMeta().GetName(),
-				Mesh: meshName,
-				Spec: item.GetSpec(),
+				Type:            string(item.GetType()),
+				Name:            item.GetMeta().GetName(),
+				Mesh:            r.meshFieldOf(meshName),
+				ResourceVersion: item.GetMeta().GetVersion(),
+				Spec:            item.GetSpec(),
 			})
 		}
 		specList := resourceSpecList{Items: items}
@@ -176,6 +251,11 @@ func (r *resourceWs) listResources(request *restful.Request, response *restful.R
 
 func (r *resourceWs) createOrUpdateResource(request *restful.Request, response *restful.Response) {
 	name := request.PathParameter("name")
+	meshName := r.namespaceOf(request)
+
+	if !r.global && !r.meshExists(request.Request.Context(), meshName, response) {
+		return
+	}
 
 	resourceRes := ResourceReqResp{
 		Spec: r.ResourceFactory().GetSpec(),
@@ -191,39 +271,47 @@ func (r *resourceWs) createOrUpdateResource(request *restful.Request, response *
 		writeError(response, 400, err.Error())
 	} else {
 		resource := r.ResourceFactory()
-		// todo(jakubdyszkiewicz) find by mesh?
-		if err := r.resourceStore.Get(request.Request.Context(), resource, store.GetByName(namespace, name)); err != nil {
-			if err.Error() == store.ErrorResourceNotFound(resource.GetType(), namespace, name).Error() {
-				r.createResource(request.Request.Context(), name, resourceRes.Spec, response)
+		if err := r.resourceStore.Get(request.Request.Context(), resource, store.GetByName(meshName, name)); err != nil {
+			if err.Error() == store.ErrorResourceNotFound(resource.GetType(), meshName, name).Error() {
+				r.createResource(request.Request.Context(), meshName, name, resourceRes.Spec, response)
 			} else {
-				log.Log.Error(err, "Could get a resource from the store", "namespace", namespace, "name", name, "type", string(resource.GetType()))
+				log.Log.Error(err, "Could get a resource from the store", "mesh", meshName, "name", name, "type", string(resource.GetType()))
 				writeError(response, 500, "Could not create a resource")
 			}
 		} else {
-			r.updateResource(request.Request.Context(), resource, resourceRes.Spec, response)
+			r.updateResource(request.Request.Context(), resource, resourceRes.Spec, resourceRes.ResourceVersion, response)
 		}
 	}
 }
 
 func (r *resourceWs) validateResourceRequest(request *restful.Request, resourceReq *ResourceReqResp) error {
 	name := request.PathParameter("name")
-	meshName := request.PathParameter("mesh")
 	if name != resourceReq.Name {
 		return errors.New("Name from the URL has to be the same as in body")
 	}
 	if string(r.ResourceFactory().GetType()) != resourceReq.Type {
 		return errors.New("Type from the URL has to be the same as in body")
 	}
-	if meshName != resourceReq.Mesh {
+	if !r.global && request.PathParameter("mesh") != resourceReq.Mesh {
 		return errors.New("Mesh from the URL has to be the same as in body")
 	}
 	return nil
 }
 
-func (r *resourceWs) createResource(ctx context.Context, name string, spec model.ResourceSpec, response *restful.Response) {
+// meshFieldOf returns the value the "mesh" field of a ResourceReqResp should
+// carry: the owning Mesh's name for ordinary resources, or "" for a resourceWs
+// serving the Mesh resource itself (which has no owning Mesh of its own).
+func (r *resourceWs) meshFieldOf(meshName string) string {
+	if r.global {
+		return ""
+	}
+	return meshName
+}
+
+func (r *resourceWs) createResource(ctx context.Context, meshName string, name string, spec model.ResourceSpec, response *restful.Response) {
 	res := r.ResourceFactory()
 	_ = res.SetSpec(spec)
-	if err := r.resourceStore.Create(ctx, res, store.CreateByName(namespace, name)); err != nil {
+	if err := r.resourceStore.Create(ctx, res, store.CreateByName(meshName, name)); err != nil {
 		log.Log.Error(err, "Could not create a resource")
 		writeError(response, 500, "Could not create a resource")
 	} else {
@@ -231,26 +319,58 @@ func (r *resourceWs) createResource(ctx context.Context, name string, spec model
 	}
 }
 
-func (r *resourceWs) updateResource(ctx context.Context, res model.Resource, spec model.ResourceSpec, response *restful.Response) {
+// updateResource delegates the optimistic-concurrency check to the store's
+// Update, rather than comparing a previously-read version in the handler:
+// passing expectedVersion through means the store's compare-and-swap (a
+// transactional check against the revision actually being written) is what
+// decides the conflict, not a read that may already be stale by the time we
+// get here.
+func (r *resourceWs) updateResource(ctx context.Context, res model.Resource, spec model.ResourceSpec, expectedVersion string, response *restful.Response) {
 	_ = res.SetSpec(spec)
-	if err := r.resourceStore.Update(ctx, res); err != nil {
+	err := r.resourceStore.Update(ctx, res, store.UpdateWithVersion(expectedVersion))
+	switch err {
+	case nil:
+		response.WriteHeader(200)
+	case store.ErrorResourceConflict:
+		writeError(response, http.StatusConflict, "resourceVersion does not match the one in the store, GET the resource and retry")
+	default:
 		log.Log.Error(err, "Could not update a resource")
 		writeError(response, 500, "Could not update a resource")
-	} else {
-		response.WriteHeader(200)
 	}
 }
 
 func (r *resourceWs) deleteResource(request *restful.Request, response *restful.Response) {
 	name := request.PathParameter("name")
+	meshName := r.namespaceOf(request)
+
+	if !r.global && !r.meshExists(request.Request.Context(), meshName, response) {
+		return
+	}
 
 	resource := r.ResourceFactory()
-	// todo(jakubdyszkiewicz) delete by mesh?
-	err := r.resourceStore.Delete(request.Request.Context(), resource, store.DeleteByName(namespace, name))
+	err := r.resourceStore.Delete(request.Request.Context(), resource, store.DeleteByName(meshName, name))
 	if err != nil {
 		writeError(response, 500, "Could not delete a resource")
-		log.Log.Error(err, "Could not delete a resource", "namespace", namespace, "name", name, "type", string(resource.GetType()))
+		log.Log.Error(err, "Could not delete a resource", "mesh", meshName, "name", name, "type", string(resource.GetType()))
+	}
+}
+
+// meshExists verifies that meshName refers to an existing Mesh resource before
+// resourceWs performs any operation scoped to it. It writes a 404 response and
+// returns false when the Mesh cannot be found, so handlers can bail out early.
+func (r *resourceWs) meshExists(ctx context.Context, meshName string, response *restful.Response) bool {
+	mesh := mesh_core.NewMeshResource()
+	err := r.resourceStore.Get(ctx, mesh, store.GetByName(mesh_core.GlobalNamespace, meshName))
+	if err != nil {
+		if err.Error() == store.ErrorResourceNotFound(mesh.GetType(), mesh_core.GlobalNamespace, meshName).Error() {
+			writeError(response, 404, fmt.Sprintf("Mesh %q not found", meshName))
+		} else {
+			log.Log.Error(err, "Could not retrieve a Mesh", "mesh", meshName)
+			writeError(response, 500, "Could not retrieve a Mesh")
+		}
+		return false
 	}
+	return true
 }
 
 func writeError(response *restful.Response, httpStatus int, msg string) {
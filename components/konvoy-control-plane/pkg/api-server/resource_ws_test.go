@@ -0,0 +1,162 @@
+package api_server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Kong/konvoy/components/konvoy-control-plane/pkg/core/resources/model"
+	"github.com/Kong/konvoy/components/konvoy-control-plane/pkg/core/resources/store"
+	"github.com/emicklei/go-restful"
+)
+
+// --- minimal model.Resource/model.ResourceMeta/model.ResourceSpec test doubles ---
+
+type testSpec struct{}
+
+func (*testSpec) Reset()         {}
+func (*testSpec) String() string { return "testSpec" }
+func (*testSpec) ProtoMessage()  {}
+
+type testMeta struct {
+	name, namespace, version string
+}
+
+func (m *testMeta) GetName() string                { return m.name }
+func (m *testMeta) GetNamespace() string           { return m.namespace }
+func (m *testMeta) GetMesh() string                { return m.namespace }
+func (m *testMeta) GetVersion() string             { return m.version }
+func (m *testMeta) GetCreationTime() time.Time     { return time.Time{} }
+func (m *testMeta) GetModificationTime() time.Time { return time.Time{} }
+
+type testResource struct {
+	meta model.ResourceMeta
+	spec model.ResourceSpec
+}
+
+func newTestResource() model.Resource { return &testResource{spec: &testSpec{}} }
+
+func (r *testResource) GetType() model.ResourceType  { return "TestType" }
+func (r *testResource) GetMeta() model.ResourceMeta  { return r.meta }
+func (r *testResource) SetMeta(m model.ResourceMeta) { r.meta = m }
+func (r *testResource) GetSpec() model.ResourceSpec  { return r.spec }
+func (r *testResource) SetSpec(s model.ResourceSpec) error {
+	r.spec = s
+	return nil
+}
+
+// --- fakeResourceStore: a store.ResourceStore double whose Get can be told to
+// report a resource missing for an exact namespace/name, matching the error
+// meshExists constructs so the 404 comparison actually fires. ---
+
+type fakeResourceStore struct {
+	store.ResourceStore
+	notFound          bool
+	notFoundNamespace string
+	notFoundName      string
+}
+
+func (f *fakeResourceStore) Get(ctx context.Context, resource model.Resource, opts ...store.GetOptionsFunc) error {
+	if f.notFound {
+		return store.ErrorResourceNotFound(resource.GetType(), f.notFoundNamespace, f.notFoundName)
+	}
+	return nil
+}
+
+func newTestResponse() (*restful.Response, *httptest.ResponseRecorder) {
+	recorder := httptest.NewRecorder()
+	return restful.NewResponse(recorder), recorder
+}
+
+// A request against a Mesh that does not exist in the store must be rejected
+// with 404, rather than silently falling through to operate on a default
+// namespace.
+func TestMeshExistsReturns404ForUnknownMesh(t *testing.T) {
+	r := &resourceWs{
+		resourceStore: &fakeResourceStore{
+			notFound:          true,
+			notFoundNamespace: "global",
+			notFoundName:      "unknown-mesh",
+		},
+	}
+
+	response, recorder := newTestResponse()
+
+	if r.meshExists(context.Background(), "unknown-mesh", response) {
+		t.Fatalf("expected meshExists to return false for an unknown Mesh")
+	}
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("expected HTTP 404, got %d", recorder.Code)
+	}
+}
+
+// updateResource must surface the store's compare-and-swap conflict as 409,
+// and must not silently downgrade it to a 500 or a success.
+func TestUpdateResourceReturns409OnStoreConflict(t *testing.T) {
+	r := &resourceWs{resourceStore: &conflictingStore{}}
+
+	resource := newTestResource()
+	resource.SetMeta(&testMeta{name: "one", namespace: "default", version: "1"})
+	response, recorder := newTestResponse()
+
+	r.updateResource(context.Background(), resource, &testSpec{}, "stale-version", response)
+
+	if recorder.Code != http.StatusConflict {
+		t.Fatalf("expected HTTP 409, got %d", recorder.Code)
+	}
+}
+
+type conflictingStore struct {
+	store.ResourceStore
+}
+
+func (c *conflictingStore) Update(ctx context.Context, resource model.Resource, fs ...store.UpdateOptionsFunc) error {
+	return store.ErrorResourceConflict
+}
+
+// TestMemoryStoreUpdateIsCompareAndSwap proves the actual race the handler
+// depends on the store to prevent: two updaters that both observed version
+// "1" before either wrote must not both succeed. Exactly one must win; the
+// other must get ErrorResourceConflict, never a silently lost write.
+func TestMemoryStoreUpdateIsCompareAndSwap(t *testing.T) {
+	s := store.NewMemoryResourceStore()
+	ctx := context.Background()
+
+	seed := newTestResource()
+	seed.SetMeta(&testMeta{name: "one", namespace: "default"})
+	if err := s.Create(ctx, seed, store.CreateByName("default", "one")); err != nil {
+		t.Fatalf("seed create failed: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	results := make([]error, 2)
+	for i := 0; i < 2; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resource := newTestResource()
+			resource.SetMeta(&testMeta{name: "one", namespace: "default", version: "1"})
+			results[i] = s.Update(ctx, resource, store.UpdateWithVersion("1"))
+		}()
+	}
+	wg.Wait()
+
+	successes, conflicts := 0, 0
+	for _, err := range results {
+		switch err {
+		case nil:
+			successes++
+		case store.ErrorResourceConflict:
+			conflicts++
+		default:
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if successes != 1 || conflicts != 1 {
+		t.Fatalf("expected exactly one success and one conflict, got %d successes and %d conflicts", successes, conflicts)
+	}
+}
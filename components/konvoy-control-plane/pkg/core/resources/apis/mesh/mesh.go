@@ -0,0 +1,87 @@
+package mesh
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/Kong/konvoy/components/konvoy-control-plane/pkg/core/resources/model"
+)
+
+// MeshType is the model.ResourceType of the Mesh resource itself, as opposed
+// to the resources a Mesh scopes (those carry their own ResourceType and are
+// stored in the namespace named after their owning Mesh).
+const MeshType model.ResourceType = "Mesh"
+
+// GlobalNamespace is the store namespace Mesh resources are kept in. A Mesh
+// is not itself scoped to another Mesh the way every other resource type
+// is, so it cannot be stored under a {mesh} namespace the way resourceWs
+// scopes everything else; see resourceWs.global.
+const GlobalNamespace = "global"
+
+// MeshResourceSpec is the Mesh resource's Spec. It is empty for now: the
+// first cut of the Mesh resource only needs an identity other resources can
+// be scoped under, not any configuration of its own.
+type MeshResourceSpec struct {
+}
+
+func (m *MeshResourceSpec) Reset()         { *m = MeshResourceSpec{} }
+func (m *MeshResourceSpec) String() string { return "MeshResourceSpec{}" }
+func (m *MeshResourceSpec) ProtoMessage()  {}
+
+// MeshResource pairs a Mesh's Spec with the Meta the store attaches to it.
+type MeshResource struct {
+	Meta model.ResourceMeta
+	Spec MeshResourceSpec
+}
+
+func (m *MeshResource) GetType() model.ResourceType     { return MeshType }
+func (m *MeshResource) GetMeta() model.ResourceMeta     { return m.Meta }
+func (m *MeshResource) SetMeta(meta model.ResourceMeta) { m.Meta = meta }
+func (m *MeshResource) GetSpec() model.ResourceSpec     { return &m.Spec }
+func (m *MeshResource) SetSpec(spec model.ResourceSpec) error {
+	meshSpec, ok := spec.(*MeshResourceSpec)
+	if !ok {
+		return errors.Errorf("invalid type %T for Spec", spec)
+	}
+	m.Spec = *meshSpec
+	return nil
+}
+
+// NewMeshResource builds an empty Mesh resource, ready to be filled in by
+// ResourceStore.Get/Create or used as the item factory for a resourceWs.
+func NewMeshResource() model.Resource {
+	return &MeshResource{}
+}
+
+// MeshResourceList is a snapshot of every Mesh known to the store.
+type MeshResourceList struct {
+	Items []*MeshResource
+}
+
+func (l *MeshResourceList) GetItemType() model.ResourceType { return MeshType }
+
+func (l *MeshResourceList) GetItems() []model.Resource {
+	items := make([]model.Resource, len(l.Items))
+	for i, item := range l.Items {
+		items[i] = item
+	}
+	return items
+}
+
+func (l *MeshResourceList) NewItem() model.Resource {
+	return NewMeshResource()
+}
+
+func (l *MeshResourceList) AddItem(resource model.Resource) error {
+	mesh, ok := resource.(*MeshResource)
+	if !ok {
+		return errors.Errorf("invalid type %T for MeshResourceList", resource)
+	}
+	l.Items = append(l.Items, mesh)
+	return nil
+}
+
+// NewMeshResourceList builds an empty MeshResourceList, ready to be filled in
+// by ResourceStore.List/Watch or used as the list factory for a resourceWs.
+func NewMeshResourceList() model.ResourceList {
+	return &MeshResourceList{}
+}
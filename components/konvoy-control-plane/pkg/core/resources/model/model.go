@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// ResourceType identifies a kind of resource, e.g. "Mesh" or "CircuitBreaker".
+type ResourceType string
+
+// ResourceSpec is the protobuf-backed payload of a Resource.
+type ResourceSpec interface {
+	proto.Message
+}
+
+// ResourceMeta carries the store-assigned identity and bookkeeping for a
+// Resource: where it lives, and which revision of it this is.
+type ResourceMeta interface {
+	GetName() string
+	GetNamespace() string
+	GetMesh() string
+	GetVersion() string
+	GetCreationTime() time.Time
+	GetModificationTime() time.Time
+}
+
+// Resource is anything the ResourceStore can Get/List/Create/Update/Delete:
+// a typed spec plus the meta the store attaches to it.
+type Resource interface {
+	GetType() ResourceType
+	GetMeta() ResourceMeta
+	SetMeta(ResourceMeta)
+	GetSpec() ResourceSpec
+	SetSpec(ResourceSpec) error
+}
+
+// ResourceList is a homogeneous collection of Resource, as returned by
+// ResourceStore.List and streamed by ResourceStore.Watch.
+type ResourceList interface {
+	GetItemType() ResourceType
+	GetItems() []Resource
+	NewItem() Resource
+	AddItem(Resource) error
+}
@@ -0,0 +1,257 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Kong/konvoy/components/konvoy-control-plane/pkg/core/resources/model"
+	"go.etcd.io/etcd/clientv3"
+	"go.etcd.io/etcd/etcdserver/api/v3rpc/rpctypes"
+	"go.etcd.io/etcd/mvcc/mvccpb"
+)
+
+// Marshaler converts a Resource to and from the bytes stored in etcd. Kept
+// separate from etcdResourceStore so tests can swap in a trivial codec
+// without pulling in the real jsonpb/proto marshaling.
+type Marshaler interface {
+	Marshal(model.Resource) ([]byte, error)
+	Unmarshal(data []byte, out model.Resource) (version string, err error)
+}
+
+// etcdResourceStore implements ResourceStore.Update as etcd3's "guaranteed
+// update": read the current object and its ModRevision, apply the caller's
+// mutation, then commit with Txn().If(ModRevision == origRev).Then(Put).
+// Else(Get). The Else branch means someone else won the race since our read;
+// we surface that to the caller as ErrorResourceConflict rather than
+// silently overwriting their write.
+type etcdResourceStore struct {
+	client    *clientv3.Client
+	marshaler Marshaler
+}
+
+func NewEtcdResourceStore(client *clientv3.Client, marshaler Marshaler) ResourceStore {
+	return &etcdResourceStore{client: client, marshaler: marshaler}
+}
+
+func resourceKey(resourceType model.ResourceType, namespace string, name string) string {
+	return fmt.Sprintf("/kuma/%s/%s/%s", resourceType, namespace, name)
+}
+
+func (s *etcdResourceStore) Get(ctx context.Context, resource model.Resource, fs ...GetOptionsFunc) error {
+	opts := NewGetOptions(fs...)
+	key := resourceKey(resource.GetType(), opts.Namespace, opts.Name)
+
+	resp, err := s.client.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	if len(resp.Kvs) == 0 {
+		return ErrorResourceNotFound(resource.GetType(), opts.Namespace, opts.Name)
+	}
+	if _, err := s.marshaler.Unmarshal(resp.Kvs[0].Value, resource); err != nil {
+		return err
+	}
+	version := strconv.FormatInt(resp.Kvs[0].ModRevision, 10)
+	resource.SetMeta(&etcdResourceMeta{namespace: opts.Namespace, name: opts.Name, version: version})
+	return nil
+}
+
+func (s *etcdResourceStore) Create(ctx context.Context, resource model.Resource, fs ...CreateOptionsFunc) error {
+	opts := NewCreateOptions(fs...)
+	key := resourceKey(resource.GetType(), opts.Namespace, opts.Name)
+
+	value, err := s.marshaler.Marshal(resource)
+	if err != nil {
+		return err
+	}
+	_, err = s.client.Put(ctx, key, string(value))
+	return err
+}
+
+// Update performs the etcd3 guaranteed-update CAS: it re-reads the key,
+// refuses to write if UpdateWithVersion named a version that no longer
+// matches the stored ModRevision, and otherwise commits transactionally
+// guarded on that same ModRevision so a writer that raced us after our read
+// still can't be silently overwritten.
+func (s *etcdResourceStore) Update(ctx context.Context, resource model.Resource, fs ...UpdateOptionsFunc) error {
+	opts := NewUpdateOptions(fs...)
+	meta := resource.GetMeta()
+	key := resourceKey(resource.GetType(), meta.GetNamespace(), meta.GetName())
+
+	getResp, err := s.client.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	if len(getResp.Kvs) == 0 {
+		return ErrorResourceNotFound(resource.GetType(), meta.GetNamespace(), meta.GetName())
+	}
+	modRevision := getResp.Kvs[0].ModRevision
+	if opts.ExpectedVersion != "" && opts.ExpectedVersion != strconv.FormatInt(modRevision, 10) {
+		return ErrorResourceConflict
+	}
+
+	value, err := s.marshaler.Marshal(resource)
+	if err != nil {
+		return err
+	}
+
+	txnResp, err := s.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.ModRevision(key), "=", modRevision)).
+		Then(clientv3.OpPut(key, string(value))).
+		Else(clientv3.OpGet(key)).
+		Commit()
+	if err != nil {
+		return err
+	}
+	if !txnResp.Succeeded {
+		// Someone else committed between our Get and our Txn: surface the
+		// conflict instead of blindly retrying, so the HTTP caller can decide
+		// whether to re-GET and resubmit. Internal callers that want the
+		// retry-until-it-sticks behavior should go through TryUpdate instead.
+		return ErrorResourceConflict
+	}
+	return nil
+}
+
+func (s *etcdResourceStore) Delete(ctx context.Context, resource model.Resource, fs ...DeleteOptionsFunc) error {
+	opts := NewDeleteOptions(fs...)
+	key := resourceKey(resource.GetType(), opts.Namespace, opts.Name)
+	_, err := s.client.Delete(ctx, key)
+	return err
+}
+
+// Watch starts an etcd clientv3.Watch on the namespace's key prefix. When
+// WatchFromResourceVersion is given, it resumes from that revision via
+// WithRev. WithCreatedNotify makes etcd ack the watch (or report a problem
+// with it) as its very first message rather than only once something
+// changes, so Watch can synchronously peek that first message before
+// returning: if the requested revision has already been compacted out of
+// etcd's history, that ack carries rpctypes.ErrCompacted and this returns
+// ErrorResourceVersionTooOld directly, instead of the caller finding out only
+// after it has already committed a 200 response and started streaming.
+func (s *etcdResourceStore) Watch(ctx context.Context, list model.ResourceList, fs ...WatchOptionsFunc) (Watcher, error) {
+	opts := NewWatchOptions(fs...)
+	prefix := fmt.Sprintf("/kuma/%s/%s/", list.GetItemType(), opts.Namespace)
+
+	watchOpts := []clientv3.OpOption{clientv3.WithPrefix(), clientv3.WithCreatedNotify()}
+	if opts.FromVersion != "" {
+		rev, err := strconv.ParseInt(opts.FromVersion, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		watchOpts = append(watchOpts, clientv3.WithRev(rev+1))
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	watchChan := s.client.Watch(ctx, prefix, watchOpts...)
+
+	ack, ok := <-watchChan
+	if !ok {
+		cancel()
+		return nil, fmt.Errorf("watch channel for %q closed before the initial ack", prefix)
+	}
+	if err := ack.Err(); err != nil {
+		cancel()
+		if err == rpctypes.ErrCompacted {
+			return nil, ErrorResourceVersionTooOld
+		}
+		return nil, err
+	}
+
+	w := &etcdWatcher{
+		resultChan: make(chan WatchEvent),
+		cancel:     cancel,
+	}
+	go w.run(watchChan, s.marshaler, list.NewItem, opts.Namespace, prefix)
+	return w, nil
+}
+
+type etcdWatcher struct {
+	resultChan chan WatchEvent
+	cancel     context.CancelFunc
+}
+
+func (w *etcdWatcher) run(watchChan clientv3.WatchChan, marshaler Marshaler, newItem func() model.Resource, namespace string, prefix string) {
+	defer close(w.resultChan)
+	for resp := range watchChan {
+		if resp.Canceled {
+			return
+		}
+		if err := resp.Err(); err != nil {
+			// Most notably rpctypes.ErrCompacted if etcd compacts the revision
+			// we're watching from out from under an already-running stream:
+			// there is nothing more we can deliver, the caller has to re-List
+			// and Watch again from a current resourceVersion. The common case
+			// where compaction has already happened is instead caught
+			// synchronously in Watch, before this goroutine is even started.
+			return
+		}
+		for _, event := range resp.Events {
+			item := newItem()
+			eventType := EventUpdated
+			var kv *mvccpb.KeyValue
+			switch event.Type {
+			case clientv3.EventTypePut:
+				kv = event.Kv
+				if event.Kv.CreateRevision == event.Kv.ModRevision {
+					eventType = EventAdded
+				}
+			case clientv3.EventTypeDelete:
+				kv = event.PrevKv
+				eventType = EventDeleted
+			}
+			if _, err := marshaler.Unmarshal(kv.Value, item); err != nil {
+				continue
+			}
+			version := strconv.FormatInt(kv.ModRevision, 10)
+			item.SetMeta(&etcdResourceMeta{namespace: namespace, name: string(kv.Key)[len(prefix):], version: version})
+			w.resultChan <- WatchEvent{Type: eventType, Resource: item}
+		}
+	}
+}
+
+func (w *etcdWatcher) ResultChan() <-chan WatchEvent { return w.resultChan }
+
+func (w *etcdWatcher) Stop() { w.cancel() }
+
+// etcdResourceMeta is the ResourceMeta etcdResourceStore attaches to every
+// resource it hands back, via Get/List or delivers via Watch, since the
+// Marshaler's Unmarshal only fills in Spec: namespace and name come from the
+// etcd key the value was read under, and version from that key's ModRevision,
+// the same revision Update compares against.
+type etcdResourceMeta struct {
+	namespace string
+	name      string
+	version   string
+}
+
+func (m *etcdResourceMeta) GetName() string                { return m.name }
+func (m *etcdResourceMeta) GetNamespace() string           { return m.namespace }
+func (m *etcdResourceMeta) GetMesh() string                { return m.namespace }
+func (m *etcdResourceMeta) GetVersion() string             { return m.version }
+func (m *etcdResourceMeta) GetCreationTime() time.Time     { return time.Time{} }
+func (m *etcdResourceMeta) GetModificationTime() time.Time { return time.Time{} }
+
+func (s *etcdResourceStore) List(ctx context.Context, list model.ResourceList, fs ...ListOptionsFunc) error {
+	opts := NewListOptions(fs...)
+	prefix := fmt.Sprintf("/kuma/%s/%s/", list.GetItemType(), opts.Namespace)
+
+	resp, err := s.client.Get(ctx, prefix, clientv3.WithPrefix())
+	if err != nil {
+		return err
+	}
+	for _, kv := range resp.Kvs {
+		item := list.NewItem()
+		if _, err := s.marshaler.Unmarshal(kv.Value, item); err != nil {
+			return err
+		}
+		version := strconv.FormatInt(kv.ModRevision, 10)
+		item.SetMeta(&etcdResourceMeta{namespace: opts.Namespace, name: string(kv.Key)[len(prefix):], version: version})
+		if err := list.AddItem(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
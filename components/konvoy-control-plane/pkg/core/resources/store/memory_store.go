@@ -0,0 +1,231 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Kong/konvoy/components/konvoy-control-plane/pkg/core/resources/model"
+)
+
+// entry is a stored resource's serialized spec plus the version it was
+// stored at, so memoryResourceStore can perform the same "check the version
+// that's actually about to be overwritten" compare-and-swap etcd does via
+// ModRevision, without needing etcd in unit tests.
+type entry struct {
+	spec    model.ResourceSpec
+	version int64
+}
+
+// memoryResourceStore is an in-memory ResourceStore, intended for unit tests
+// of the optimistic-concurrency and watch contracts rather than for
+// production use. Its Watch does not retain history: WatchFromResourceVersion
+// is accepted but has no compacted-revision case to detect, so it never
+// returns ErrorResourceVersionTooOld - it simply streams changes from "now".
+type memoryResourceStore struct {
+	mu       sync.Mutex
+	entries  map[string]*entry
+	watchers map[string][]*memoryWatcher
+}
+
+func NewMemoryResourceStore() ResourceStore {
+	return &memoryResourceStore{
+		entries:  map[string]*entry{},
+		watchers: map[string][]*memoryWatcher{},
+	}
+}
+
+func memoryKey(resourceType model.ResourceType, namespace string, name string) string {
+	return fmt.Sprintf("%s/%s/%s", resourceType, namespace, name)
+}
+
+func (s *memoryResourceStore) Create(ctx context.Context, resource model.Resource, fs ...CreateOptionsFunc) error {
+	opts := NewCreateOptions(fs...)
+	key := memoryKey(resource.GetType(), opts.Namespace, opts.Name)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = &entry{spec: resource.GetSpec(), version: 1}
+	s.publishLocked(resource.GetType(), opts.Namespace, opts.Name, 1, resource.GetSpec(), EventAdded)
+	return nil
+}
+
+func (s *memoryResourceStore) Get(ctx context.Context, resource model.Resource, fs ...GetOptionsFunc) error {
+	opts := NewGetOptions(fs...)
+	key := memoryKey(resource.GetType(), opts.Namespace, opts.Name)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[key]
+	if !ok {
+		return ErrorResourceNotFound(resource.GetType(), opts.Namespace, opts.Name)
+	}
+	resource.SetMeta(&memoryResourceMeta{namespace: opts.Namespace, name: opts.Name, version: e.version})
+	return resource.SetSpec(e.spec)
+}
+
+// Update is the compare-and-swap the HTTP layer relies on: it checks
+// ExpectedVersion against the version that is actually about to be
+// overwritten while holding the lock, so two concurrent callers that both
+// read the same stale version can't both succeed - exactly the race a
+// read-then-compare check in the HTTP handler would have let through.
+func (s *memoryResourceStore) Update(ctx context.Context, resource model.Resource, fs ...UpdateOptionsFunc) error {
+	opts := NewUpdateOptions(fs...)
+	meta := resource.GetMeta()
+	key := memoryKey(resource.GetType(), meta.GetNamespace(), meta.GetName())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[key]
+	if !ok {
+		return ErrorResourceNotFound(resource.GetType(), meta.GetNamespace(), meta.GetName())
+	}
+	if opts.ExpectedVersion != "" && opts.ExpectedVersion != fmt.Sprintf("%d", e.version) {
+		return ErrorResourceConflict
+	}
+	newVersion := e.version + 1
+	s.entries[key] = &entry{spec: resource.GetSpec(), version: newVersion}
+	s.publishLocked(resource.GetType(), meta.GetNamespace(), meta.GetName(), newVersion, resource.GetSpec(), EventUpdated)
+	return nil
+}
+
+func (s *memoryResourceStore) Delete(ctx context.Context, resource model.Resource, fs ...DeleteOptionsFunc) error {
+	opts := NewDeleteOptions(fs...)
+	key := memoryKey(resource.GetType(), opts.Namespace, opts.Name)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[key]
+	if !ok {
+		return nil
+	}
+	delete(s.entries, key)
+	s.publishLocked(resource.GetType(), opts.Namespace, opts.Name, e.version, e.spec, EventDeleted)
+	return nil
+}
+
+// Watch registers a memoryWatcher for list's item type and namespace. It is
+// unregistered, and its ResultChan closed, only once Stop is called: ctx is
+// not observed, so a done context leaves the watcher registered and its
+// ResultChan open.
+func (s *memoryResourceStore) Watch(ctx context.Context, list model.ResourceList, fs ...WatchOptionsFunc) (Watcher, error) {
+	opts := NewWatchOptions(fs...)
+	prefix := fmt.Sprintf("%s/%s/", list.GetItemType(), opts.Namespace)
+
+	w := &memoryWatcher{
+		store:      s,
+		prefix:     prefix,
+		resultChan: make(chan WatchEvent, 16),
+	}
+
+	s.mu.Lock()
+	s.watchers[prefix] = append(s.watchers[prefix], w)
+	s.mu.Unlock()
+
+	return w, nil
+}
+
+// publishLocked fans a change out to every watcher registered for a prefix
+// that key belongs to. Must be called with s.mu held.
+func (s *memoryResourceStore) publishLocked(resourceType model.ResourceType, namespace string, name string, version int64, spec model.ResourceSpec, eventType EventType) {
+	key := memoryKey(resourceType, namespace, name)
+	for prefix, watchers := range s.watchers {
+		if len(key) < len(prefix) || key[:len(prefix)] != prefix {
+			continue
+		}
+		resource := &memoryResource{
+			resourceType: resourceType,
+			meta:         &memoryResourceMeta{namespace: namespace, name: name, version: version},
+			spec:         spec,
+		}
+		for _, w := range watchers {
+			select {
+			case w.resultChan <- WatchEvent{Type: eventType, Resource: resource}:
+			default:
+				// A slow watcher must not block Create/Update/Delete for everyone
+				// else; it simply misses this event and keeps receiving later ones.
+			}
+		}
+	}
+}
+
+func (s *memoryResourceStore) removeWatcher(w *memoryWatcher) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	watchers := s.watchers[w.prefix]
+	for i, other := range watchers {
+		if other == w {
+			s.watchers[w.prefix] = append(watchers[:i], watchers[i+1:]...)
+			break
+		}
+	}
+}
+
+type memoryWatcher struct {
+	store      *memoryResourceStore
+	prefix     string
+	resultChan chan WatchEvent
+}
+
+func (w *memoryWatcher) ResultChan() <-chan WatchEvent { return w.resultChan }
+
+func (w *memoryWatcher) Stop() {
+	w.store.removeWatcher(w)
+	close(w.resultChan)
+}
+
+// memoryResourceMeta is the ResourceMeta memoryResourceStore attaches to
+// every resource it hands back, via Get/List or delivers via Watch.
+// Creation/modification times aren't tracked by this in-memory store, so
+// they read as the zero time.
+type memoryResourceMeta struct {
+	namespace string
+	name      string
+	version   int64
+}
+
+func (m *memoryResourceMeta) GetName() string                { return m.name }
+func (m *memoryResourceMeta) GetNamespace() string           { return m.namespace }
+func (m *memoryResourceMeta) GetMesh() string                { return m.namespace }
+func (m *memoryResourceMeta) GetVersion() string             { return fmt.Sprintf("%d", m.version) }
+func (m *memoryResourceMeta) GetCreationTime() time.Time     { return time.Time{} }
+func (m *memoryResourceMeta) GetModificationTime() time.Time { return time.Time{} }
+
+type memoryResource struct {
+	resourceType model.ResourceType
+	meta         model.ResourceMeta
+	spec         model.ResourceSpec
+}
+
+func (r *memoryResource) GetType() model.ResourceType     { return r.resourceType }
+func (r *memoryResource) GetMeta() model.ResourceMeta     { return r.meta }
+func (r *memoryResource) SetMeta(meta model.ResourceMeta) { r.meta = meta }
+func (r *memoryResource) GetSpec() model.ResourceSpec     { return r.spec }
+func (r *memoryResource) SetSpec(spec model.ResourceSpec) error {
+	r.spec = spec
+	return nil
+}
+
+func (s *memoryResourceStore) List(ctx context.Context, list model.ResourceList, fs ...ListOptionsFunc) error {
+	opts := NewListOptions(fs...)
+	prefix := fmt.Sprintf("%s/%s/", list.GetItemType(), opts.Namespace)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, e := range s.entries {
+		if len(key) < len(prefix) || key[:len(prefix)] != prefix {
+			continue
+		}
+		name := key[len(prefix):]
+		item := list.NewItem()
+		item.SetMeta(&memoryResourceMeta{namespace: opts.Namespace, name: name, version: e.version})
+		if err := item.SetSpec(e.spec); err != nil {
+			return err
+		}
+		if err := list.AddItem(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Kong/konvoy/components/konvoy-control-plane/pkg/core/resources/model"
+)
+
+type watchTestSpec struct{}
+
+func (*watchTestSpec) Reset()         {}
+func (*watchTestSpec) String() string { return "watchTestSpec" }
+func (*watchTestSpec) ProtoMessage()  {}
+
+type watchTestResourceList struct {
+	items []model.Resource
+}
+
+func (l *watchTestResourceList) GetItemType() model.ResourceType { return "TestType" }
+func (l *watchTestResourceList) GetItems() []model.Resource      { return l.items }
+func (l *watchTestResourceList) NewItem() model.Resource {
+	return &memoryResource{resourceType: "TestType", spec: &watchTestSpec{}}
+}
+func (l *watchTestResourceList) AddItem(r model.Resource) error {
+	l.items = append(l.items, r)
+	return nil
+}
+
+// TestMemoryStoreWatchDeliversCreateEvent proves Watch is a real, working
+// notification path and not dead scaffolding: a Create made after Watch has
+// registered must be observed on the returned Watcher's ResultChan.
+func TestMemoryStoreWatchDeliversCreateEvent(t *testing.T) {
+	s := NewMemoryResourceStore()
+	ctx := context.Background()
+
+	w, err := s.Watch(ctx, &watchTestResourceList{}, WatchByNamespace("default"))
+	if err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+	defer w.Stop()
+
+	resource := &memoryResource{resourceType: "TestType", spec: &watchTestSpec{}}
+	if err := s.Create(ctx, resource, CreateByName("default", "one")); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	select {
+	case event := <-w.ResultChan():
+		if event.Type != EventAdded {
+			t.Fatalf("expected EventAdded, got %v", event.Type)
+		}
+		if event.Resource.GetMeta().GetName() != "one" {
+			t.Fatalf("expected resource name %q, got %q", "one", event.Resource.GetMeta().GetName())
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for watch event")
+	}
+}
+
+// TestMemoryStoreGetPopulatesResourceMeta proves Get attaches a ResourceMeta
+// to the resource it fills in, rather than leaving GetMeta() nil: it goes
+// through the real Create then Get sequence resourceWs.findResource uses,
+// not a hand-wired SetMeta, since that would miss exactly this bug.
+func TestMemoryStoreGetPopulatesResourceMeta(t *testing.T) {
+	s := NewMemoryResourceStore()
+	ctx := context.Background()
+
+	created := &memoryResource{resourceType: "TestType", spec: &watchTestSpec{}}
+	if err := s.Create(ctx, created, CreateByName("default", "one")); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	fetched := &memoryResource{resourceType: "TestType", spec: &watchTestSpec{}}
+	if err := s.Get(ctx, fetched, GetByName("default", "one")); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+
+	meta := fetched.GetMeta()
+	if meta == nil {
+		t.Fatal("expected Get to populate ResourceMeta, got nil")
+	}
+	if meta.GetName() != "one" {
+		t.Fatalf("expected name %q, got %q", "one", meta.GetName())
+	}
+	if meta.GetNamespace() != "default" {
+		t.Fatalf("expected namespace %q, got %q", "default", meta.GetNamespace())
+	}
+	if meta.GetVersion() != "1" {
+		t.Fatalf("expected version %q, got %q", "1", meta.GetVersion())
+	}
+}
+
+// TestMemoryStoreUpdateAfterGetSucceeds proves the Get-then-Update sequence
+// createOrUpdateResource relies on actually works end to end: Update reads
+// the namespace/name off the ResourceMeta Get attached, not off a meta the
+// test wired in by hand.
+func TestMemoryStoreUpdateAfterGetSucceeds(t *testing.T) {
+	s := NewMemoryResourceStore()
+	ctx := context.Background()
+
+	if err := s.Create(ctx, &memoryResource{resourceType: "TestType", spec: &watchTestSpec{}}, CreateByName("default", "one")); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	fetched := &memoryResource{resourceType: "TestType", spec: &watchTestSpec{}}
+	if err := s.Get(ctx, fetched, GetByName("default", "one")); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+
+	if err := s.Update(ctx, fetched, UpdateWithVersion(fetched.GetMeta().GetVersion())); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	updated := &memoryResource{resourceType: "TestType", spec: &watchTestSpec{}}
+	if err := s.Get(ctx, updated, GetByName("default", "one")); err != nil {
+		t.Fatalf("Get after Update failed: %v", err)
+	}
+	if updated.GetMeta().GetVersion() != "2" {
+		t.Fatalf("expected version %q after Update, got %q", "2", updated.GetMeta().GetVersion())
+	}
+}
+
+// TestMemoryStoreListPopulatesResourceMeta proves List attaches a
+// ResourceMeta to every item it returns, the same way Get does.
+func TestMemoryStoreListPopulatesResourceMeta(t *testing.T) {
+	s := NewMemoryResourceStore()
+	ctx := context.Background()
+
+	if err := s.Create(ctx, &memoryResource{resourceType: "TestType", spec: &watchTestSpec{}}, CreateByName("default", "one")); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	list := &watchTestResourceList{}
+	if err := s.List(ctx, list, ListByNamespace("default")); err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(list.GetItems()) != 1 {
+		t.Fatalf("expected 1 item, got %d", len(list.GetItems()))
+	}
+
+	meta := list.GetItems()[0].GetMeta()
+	if meta == nil {
+		t.Fatal("expected List to populate ResourceMeta, got nil")
+	}
+	if meta.GetName() != "one" {
+		t.Fatalf("expected name %q, got %q", "one", meta.GetName())
+	}
+}
+
+// TestMemoryStoreWatchStopClosesResultChan proves Stop actually tears the
+// watcher down rather than leaking it: once stopped, ResultChan must close
+// and deliver no further events even if changes keep happening.
+func TestMemoryStoreWatchStopClosesResultChan(t *testing.T) {
+	s := NewMemoryResourceStore()
+	ctx := context.Background()
+
+	w, err := s.Watch(ctx, &watchTestResourceList{}, WatchByNamespace("default"))
+	if err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+	w.Stop()
+
+	select {
+	case _, ok := <-w.ResultChan():
+		if ok {
+			t.Fatal("expected ResultChan to be closed after Stop")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for ResultChan to close")
+	}
+}
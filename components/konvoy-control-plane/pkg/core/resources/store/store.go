@@ -0,0 +1,196 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Kong/konvoy/components/konvoy-control-plane/pkg/core/resources/model"
+)
+
+// ResourceStore is the persistence boundary for every Resource type.
+// Update is optimistic-concurrency-safe: when called with UpdateWithVersion,
+// an implementation must apply the write only if the stored resource is
+// still at that version, and return ErrorResourceConflict otherwise.
+type ResourceStore interface {
+	Create(ctx context.Context, resource model.Resource, fs ...CreateOptionsFunc) error
+	Update(ctx context.Context, resource model.Resource, fs ...UpdateOptionsFunc) error
+	Delete(ctx context.Context, resource model.Resource, fs ...DeleteOptionsFunc) error
+	Get(ctx context.Context, resource model.Resource, fs ...GetOptionsFunc) error
+	List(ctx context.Context, list model.ResourceList, fs ...ListOptionsFunc) error
+	// Watch streams subsequent changes to resources of list's item type in the
+	// given namespace, optionally resuming from a resourceVersion previously
+	// observed via ResourceMeta.GetVersion(). It returns ErrorResourceVersionTooOld
+	// when that version has already fallen out of the implementation's history,
+	// in which case the caller must re-List and Watch again.
+	Watch(ctx context.Context, list model.ResourceList, fs ...WatchOptionsFunc) (Watcher, error)
+}
+
+func ErrorResourceNotFound(resourceType model.ResourceType, namespace string, name string) error {
+	return fmt.Errorf("resource not found: type=%q namespace=%q name=%q", resourceType, namespace, name)
+}
+
+// ErrorResourceConflict is returned by Update when UpdateWithVersion named a
+// version that no longer matches what's stored.
+var ErrorResourceConflict = fmt.Errorf("resourceVersion does not match the one in the store")
+
+// ErrorResourceVersionTooOld is returned by Watch when WatchFromResourceVersion
+// named a version the implementation no longer has history for. The caller
+// must re-List to get a current snapshot and resourceVersion, then Watch again
+// from there.
+var ErrorResourceVersionTooOld = fmt.Errorf("resourceVersion is too old, re-list and watch again")
+
+type GetOptions struct {
+	Namespace string
+	Name      string
+}
+type GetOptionsFunc func(*GetOptions)
+
+func GetByName(namespace string, name string) GetOptionsFunc {
+	return func(o *GetOptions) { o.Namespace = namespace; o.Name = name }
+}
+
+func NewGetOptions(fs ...GetOptionsFunc) GetOptions {
+	opts := GetOptions{}
+	for _, f := range fs {
+		f(&opts)
+	}
+	return opts
+}
+
+type ListOptions struct {
+	Namespace string
+}
+type ListOptionsFunc func(*ListOptions)
+
+func ListByNamespace(namespace string) ListOptionsFunc {
+	return func(o *ListOptions) { o.Namespace = namespace }
+}
+
+func NewListOptions(fs ...ListOptionsFunc) ListOptions {
+	opts := ListOptions{}
+	for _, f := range fs {
+		f(&opts)
+	}
+	return opts
+}
+
+type CreateOptions struct {
+	Namespace string
+	Name      string
+}
+type CreateOptionsFunc func(*CreateOptions)
+
+func CreateByName(namespace string, name string) CreateOptionsFunc {
+	return func(o *CreateOptions) { o.Namespace = namespace; o.Name = name }
+}
+
+func NewCreateOptions(fs ...CreateOptionsFunc) CreateOptions {
+	opts := CreateOptions{}
+	for _, f := range fs {
+		f(&opts)
+	}
+	return opts
+}
+
+type DeleteOptions struct {
+	Namespace string
+	Name      string
+}
+type DeleteOptionsFunc func(*DeleteOptions)
+
+func DeleteByName(namespace string, name string) DeleteOptionsFunc {
+	return func(o *DeleteOptions) { o.Namespace = namespace; o.Name = name }
+}
+
+func NewDeleteOptions(fs ...DeleteOptionsFunc) DeleteOptions {
+	opts := DeleteOptions{}
+	for _, f := range fs {
+		f(&opts)
+	}
+	return opts
+}
+
+// UpdateOptions.ExpectedVersion, when set, is the resourceVersion the caller
+// last observed. Update must fail with ErrorResourceConflict if the stored
+// resource has since moved on.
+type UpdateOptions struct {
+	ExpectedVersion string
+}
+type UpdateOptionsFunc func(*UpdateOptions)
+
+func UpdateWithVersion(expectedVersion string) UpdateOptionsFunc {
+	return func(o *UpdateOptions) { o.ExpectedVersion = expectedVersion }
+}
+
+func NewUpdateOptions(fs ...UpdateOptionsFunc) UpdateOptions {
+	opts := UpdateOptions{}
+	for _, f := range fs {
+		f(&opts)
+	}
+	return opts
+}
+
+// EventType categorizes a WatchEvent the same way Kubernetes watch events do.
+type EventType string
+
+const (
+	EventAdded   EventType = "ADDED"
+	EventUpdated EventType = "MODIFIED"
+	EventDeleted EventType = "DELETED"
+)
+
+// WatchEvent is a single change observed by a Watcher.
+type WatchEvent struct {
+	Type     EventType
+	Resource model.Resource
+}
+
+// Watcher streams WatchEvents until Stop is called or the context passed to
+// Watch is done, at which point ResultChan is closed.
+type Watcher interface {
+	ResultChan() <-chan WatchEvent
+	Stop()
+}
+
+type WatchOptions struct {
+	Namespace   string
+	FromVersion string
+}
+type WatchOptionsFunc func(*WatchOptions)
+
+func WatchByNamespace(namespace string) WatchOptionsFunc {
+	return func(o *WatchOptions) { o.Namespace = namespace }
+}
+
+func WatchFromResourceVersion(version string) WatchOptionsFunc {
+	return func(o *WatchOptions) { o.FromVersion = version }
+}
+
+func NewWatchOptions(fs ...WatchOptionsFunc) WatchOptions {
+	opts := WatchOptions{}
+	for _, f := range fs {
+		f(&opts)
+	}
+	return opts
+}
+
+// TryUpdate implements the "guaranteed update" pattern for internal callers
+// that don't have an HTTP client to hand a 409 back to: Get the current
+// resource, let mutate adjust it in place, then Update with the version just
+// observed. On ErrorResourceConflict it re-reads and retries the mutation.
+func TryUpdate(ctx context.Context, rs ResourceStore, resource model.Resource, namespace string, name string, mutate func(model.Resource) error) error {
+	for {
+		if err := rs.Get(ctx, resource, GetByName(namespace, name)); err != nil {
+			return err
+		}
+		observedVersion := resource.GetMeta().GetVersion()
+		if err := mutate(resource); err != nil {
+			return err
+		}
+		err := rs.Update(ctx, resource, UpdateWithVersion(observedVersion))
+		if err == ErrorResourceConflict {
+			continue
+		}
+		return err
+	}
+}
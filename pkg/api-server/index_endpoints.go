@@ -6,12 +6,68 @@ import (
 	"github.com/emicklei/go-restful"
 
 	"github.com/kumahq/kuma/pkg/api-server/types"
+	"github.com/kumahq/kuma/pkg/cluster"
 	kuma_version "github.com/kumahq/kuma/pkg/version"
 )
 
-var APIIndexResponseFn = kumaAPIIndexResponse
+// IndexResponseFn renders the "/" index response. It is an interface rather
+// than a bare func so a ClusterRegistry-backed implementation can report the
+// peer control planes it has gossiped with, while a single-node deployment
+// can keep using kumaAPIIndexResponse below.
+type IndexResponseFn interface {
+	IndexResponse(hostname string, instanceId string, clusterId string) interface{}
+}
+
+// StatusFn reports the health of the subsystems backing this API server, for
+// the "/status" endpoint.
+type StatusFn interface {
+	Status() interface{}
+}
+
+var APIIndexResponseFn IndexResponseFn = indexResponseFn(kumaAPIIndexResponse)
+
+// indexResponseFn adapts a plain func to IndexResponseFn.
+type indexResponseFn func(hostname string, instanceId string, clusterId string) interface{}
+
+func (f indexResponseFn) IndexResponse(hostname string, instanceId string, clusterId string) interface{} {
+	return f(hostname, instanceId, clusterId)
+}
 
-func addIndexWsEndpoints(ws *restful.WebService, getInstanceId func() string, getClusterId func() string) error {
+// clusterIndexResponseFn reports this control plane's known federation peers
+// alongside the usual index fields.
+type clusterIndexResponseFn struct {
+	registry cluster.Registry
+}
+
+// NewClusterIndexResponseFn builds an IndexResponseFn that augments the index
+// response with the peer control planes tracked by registry.
+func NewClusterIndexResponseFn(registry cluster.Registry) IndexResponseFn {
+	return &clusterIndexResponseFn{registry: registry}
+}
+
+func (c *clusterIndexResponseFn) IndexResponse(hostname string, instanceId string, clusterId string) interface{} {
+	peers := c.registry.Peers()
+	peerInfos := make([]types.PeerInfo, len(peers))
+	for i, peer := range peers {
+		peerInfos[i] = types.PeerInfo{
+			ClusterId: peer.ClusterId,
+			Address:   peer.Address,
+			Role:      string(peer.Role),
+			LastSeen:  peer.LastSeen,
+		}
+	}
+	return types.IndexResponse{
+		Hostname:   hostname,
+		Tagline:    kuma_version.Product,
+		Version:    kuma_version.Build.Version,
+		InstanceId: instanceId,
+		ClusterId:  clusterId,
+		Role:       string(c.registry.Self().Role),
+		Peers:      peerInfos,
+	}
+}
+
+func addIndexWsEndpoints(ws *restful.WebService, getInstanceId func() string, getClusterId func() string, statusFn StatusFn) error {
 	hostname, err := os.Hostname()
 	var instanceId string
 	var clusterId string
@@ -27,12 +83,21 @@ func addIndexWsEndpoints(ws *restful.WebService, getInstanceId func() string, ge
 			clusterId = getClusterId()
 		}
 
-		response := APIIndexResponseFn(hostname, instanceId, clusterId)
+		response := APIIndexResponseFn.IndexResponse(hostname, instanceId, clusterId)
 
 		if err := resp.WriteAsJson(response); err != nil {
 			log.Error(err, "Could not write the index response")
 		}
 	}))
+
+	if statusFn != nil {
+		ws.Route(ws.GET("/status").To(func(req *restful.Request, resp *restful.Response) {
+			if err := resp.WriteAsJson(statusFn.Status()); err != nil {
+				log.Error(err, "Could not write the status response")
+			}
+		}))
+	}
+
 	return nil
 }
 
@@ -0,0 +1,36 @@
+package api_server
+
+import (
+	"github.com/kumahq/kuma/pkg/api-server/types"
+)
+
+// HealthCheck reports whether a subsystem is reachable, and an optional
+// human-readable reason when it is not.
+type HealthCheck func() (healthy bool, reason string)
+
+// componentStatusFn is the default StatusFn, reporting the health of the
+// resource store backend and the xDS server via the HealthChecks it was
+// built with.
+type componentStatusFn struct {
+	storeHealthCheck HealthCheck
+	xdsHealthCheck   HealthCheck
+}
+
+// NewStatusFn builds a StatusFn that reports the health of the resource store
+// and the xDS server for the "/status" endpoint.
+func NewStatusFn(storeHealthCheck HealthCheck, xdsHealthCheck HealthCheck) StatusFn {
+	return &componentStatusFn{
+		storeHealthCheck: storeHealthCheck,
+		xdsHealthCheck:   xdsHealthCheck,
+	}
+}
+
+func (s *componentStatusFn) Status() interface{} {
+	storeHealthy, storeReason := s.storeHealthCheck()
+	xdsHealthy, xdsReason := s.xdsHealthCheck()
+
+	return types.StatusResponse{
+		Store: types.ComponentStatus{Healthy: storeHealthy, Reason: storeReason},
+		Xds:   types.ComponentStatus{Healthy: xdsHealthy, Reason: xdsReason},
+	}
+}
@@ -0,0 +1,38 @@
+package types
+
+import "time"
+
+// IndexResponse is served from "/" and identifies this control plane
+// instance, plus the federation peers it knows about when fronted by a
+// cluster.Registry (see NewClusterIndexResponseFn).
+type IndexResponse struct {
+	Hostname   string     `json:"hostname"`
+	Tagline    string     `json:"tagline"`
+	Version    string     `json:"version"`
+	InstanceId string     `json:"instanceId"`
+	ClusterId  string     `json:"clusterId,omitempty"`
+	Role       string     `json:"role,omitempty"`
+	Peers      []PeerInfo `json:"peers,omitempty"`
+}
+
+// PeerInfo describes one federation peer as reported alongside IndexResponse.
+type PeerInfo struct {
+	ClusterId string    `json:"clusterId"`
+	Address   string    `json:"address"`
+	Role      string    `json:"role"`
+	LastSeen  time.Time `json:"lastSeen"`
+}
+
+// StatusResponse is served from "/status" and reports the health of the
+// subsystems the API server depends on.
+type StatusResponse struct {
+	Store ComponentStatus `json:"store"`
+	Xds   ComponentStatus `json:"xds"`
+}
+
+// ComponentStatus reports whether a single subsystem is reachable, and an
+// optional human-readable reason when it is not.
+type ComponentStatus struct {
+	Healthy bool   `json:"healthy"`
+	Reason  string `json:"reason,omitempty"`
+}
@@ -0,0 +1,142 @@
+package cluster
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// GossipConfig configures how this control plane exchanges Peer info with
+// the rest of the federation.
+type GossipConfig struct {
+	// PeerAddresses are the other control planes' gossip endpoints to push
+	// this instance's Peer to periodically. Use an "https://" scheme, e.g.
+	// "https://remote-1.kuma:5682" - Gossip itself doesn't terminate or
+	// require TLS, it is whatever the address's scheme and the receiving
+	// control plane's listener negotiate.
+	PeerAddresses []string
+	// Interval is how often this instance pushes itself to every configured
+	// address.
+	Interval time.Duration
+	// AuthToken, when set, is sent as a Bearer token on every push and
+	// required (and constant-time compared) by Handler, so a peer's gossip
+	// endpoint can't be fed forged Peer entries by anything that can merely
+	// reach it on the network. Every control plane in the federation must be
+	// configured with the same AuthToken.
+	AuthToken string
+}
+
+// Gossip is the "existing control-plane connection" NewRegistry's doc
+// comment refers to: it keeps a Registry's Peers current by periodically
+// pushing this instance's own identity to every configured peer address, and
+// by exposing an HTTP handler that every control plane in the federation
+// runs to receive the same pushes from the others. A plain periodic HTTP
+// push rather than a dedicated streaming protocol, traded off for being
+// simple enough to land in one piece; see Handler for the receiving side.
+// AuthToken is the only authentication Gossip does itself - encryption in
+// transit is left to the scheme PeerAddresses are configured with.
+type Gossip struct {
+	registry Registry
+	client   *http.Client
+	config   GossipConfig
+}
+
+func NewGossip(registry Registry, config GossipConfig) *Gossip {
+	return &Gossip{
+		registry: registry,
+		client:   &http.Client{Timeout: 5 * time.Second},
+		config:   config,
+	}
+}
+
+// Start pushes this instance's Peer to every configured address on
+// config.Interval, until stop is closed. It returns immediately if no peer
+// addresses are configured, e.g. for a single-node deployment.
+func (g *Gossip) Start(stop <-chan struct{}) error {
+	if len(g.config.PeerAddresses) == 0 {
+		return nil
+	}
+
+	ticker := time.NewTicker(g.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+			g.pushToAllPeers()
+		}
+	}
+}
+
+func (g *Gossip) pushToAllPeers() {
+	self := g.registry.Self()
+	self.LastSeen = time.Now()
+
+	body, err := json.Marshal(self)
+	if err != nil {
+		return
+	}
+	for _, address := range g.config.PeerAddresses {
+		g.push(address, body)
+	}
+}
+
+func (g *Gossip) push(address string, body []byte) {
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/gossip", address), bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if g.config.AuthToken != "" {
+		req.Header.Set("Authorization", "Bearer "+g.config.AuthToken)
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		// Peers come and go during rollouts/partitions; there's nothing
+		// actionable to do here, the next tick will try again.
+		return
+	}
+	resp.Body.Close()
+}
+
+// Handler is the receiving side of Gossip: decode the pushed Peer and record
+// it, so the endpoint wherever this is mounted is what PeerAddresses on
+// every other control plane should point at. It rejects the request with 401
+// if AuthToken is configured and the request's Bearer token doesn't match.
+func (g *Gossip) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !g.authorized(r) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var peer Peer
+		if err := json.NewDecoder(r.Body).Decode(&peer); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		g.registry.Update(peer)
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// authorized reports whether r carries the Bearer token AuthToken requires.
+// It is a no-op check (always true) when AuthToken isn't configured, and
+// uses a constant-time comparison so a misconfigured deployment can't be
+// probed for the token one byte at a time via response timing.
+func (g *Gossip) authorized(r *http.Request) bool {
+	if g.config.AuthToken == "" {
+		return true
+	}
+	const prefix = "Bearer "
+	header := r.Header.Get("Authorization")
+	if len(header) != len(prefix)+len(g.config.AuthToken) || header[:len(prefix)] != prefix {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(header[len(prefix):]), []byte(g.config.AuthToken)) == 1
+}
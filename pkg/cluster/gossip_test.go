@@ -0,0 +1,149 @@
+package cluster
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestGossipPushUpdatesPeerRegistry proves the transport actually works end
+// to end: a Gossip pushing on one instance must land on another instance's
+// Registry via Handler, not just exist as an unused method.
+func TestGossipPushUpdatesPeerRegistry(t *testing.T) {
+	remoteRegistry := NewRegistry(Peer{ClusterId: "remote-1", Role: Remote})
+	remoteGossip := NewGossip(remoteRegistry, GossipConfig{})
+	server := httptest.NewServer(remoteGossip.Handler())
+	defer server.Close()
+
+	globalRegistry := NewRegistry(Peer{ClusterId: "global", Role: Global})
+	globalGossip := NewGossip(globalRegistry, GossipConfig{
+		PeerAddresses: []string{server.URL},
+		Interval:      time.Millisecond,
+	})
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = globalGossip.Start(stop)
+	}()
+
+	deadline := time.After(time.Second)
+	for {
+		if len(remoteRegistry.Peers()) > 0 {
+			break
+		}
+		select {
+		case <-deadline:
+			close(stop)
+			<-done
+			t.Fatal("timed out waiting for the pushed Peer to reach the remote Registry")
+		case <-time.After(time.Millisecond):
+		}
+	}
+	close(stop)
+	<-done
+
+	peers := remoteRegistry.Peers()
+	if len(peers) != 1 || peers[0].ClusterId != "global" {
+		t.Fatalf("expected remote registry to know about peer %q, got %+v", "global", peers)
+	}
+}
+
+// TestGossipHandlerRejectsMissingOrWrongAuthToken proves Handler actually
+// enforces AuthToken rather than just accepting whatever shows up: a push
+// with no token, or the wrong token, must be rejected and must not reach the
+// Registry.
+func TestGossipHandlerRejectsMissingOrWrongAuthToken(t *testing.T) {
+	registry := NewRegistry(Peer{ClusterId: "remote-1", Role: Remote})
+	gossip := NewGossip(registry, GossipConfig{AuthToken: "correct-token"})
+	server := httptest.NewServer(gossip.Handler())
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/gossip", nil)
+	if err != nil {
+		t.Fatalf("building request failed: %v", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected %d with no Authorization header, got %d", http.StatusUnauthorized, resp.StatusCode)
+	}
+
+	req.Header.Set("Authorization", "Bearer wrong-token")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected %d with the wrong token, got %d", http.StatusUnauthorized, resp.StatusCode)
+	}
+
+	if len(registry.Peers()) != 0 {
+		t.Fatalf("expected no peers to be recorded from unauthorized pushes, got %+v", registry.Peers())
+	}
+}
+
+// TestGossipPushSendsConfiguredAuthToken proves Gossip.push actually attaches
+// AuthToken to its requests, end to end through Handler's own check: a push
+// between two Gossips configured with the same AuthToken must still land on
+// the remote Registry.
+func TestGossipPushSendsConfiguredAuthToken(t *testing.T) {
+	remoteRegistry := NewRegistry(Peer{ClusterId: "remote-1", Role: Remote})
+	remoteGossip := NewGossip(remoteRegistry, GossipConfig{AuthToken: "shared-secret"})
+	server := httptest.NewServer(remoteGossip.Handler())
+	defer server.Close()
+
+	globalGossip := NewGossip(NewRegistry(Peer{ClusterId: "global", Role: Global}), GossipConfig{
+		PeerAddresses: []string{server.URL},
+		Interval:      time.Millisecond,
+		AuthToken:     "shared-secret",
+	})
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = globalGossip.Start(stop)
+	}()
+
+	deadline := time.After(time.Second)
+	for {
+		if len(remoteRegistry.Peers()) > 0 {
+			break
+		}
+		select {
+		case <-deadline:
+			close(stop)
+			<-done
+			t.Fatal("timed out waiting for the authorized push to reach the remote Registry")
+		case <-time.After(time.Millisecond):
+		}
+	}
+	close(stop)
+	<-done
+}
+
+// TestRegistryPeersExcludesSelfAndDedupes proves Peers() never reports this
+// instance's own identity, and that repeated Update calls for the same
+// ClusterId replace rather than accumulate.
+func TestRegistryPeersExcludesSelfAndDedupes(t *testing.T) {
+	registry := NewRegistry(Peer{ClusterId: "self", Role: Global})
+
+	registry.Update(Peer{ClusterId: "self", Role: Global})
+	registry.Update(Peer{ClusterId: "remote-1", Role: Remote, Address: "first"})
+	registry.Update(Peer{ClusterId: "remote-1", Role: Remote, Address: "second"})
+
+	peers := registry.Peers()
+	if len(peers) != 1 {
+		t.Fatalf("expected exactly one peer (self excluded, remote-1 deduped), got %d: %+v", len(peers), peers)
+	}
+	if peers[0].Address != "second" {
+		t.Fatalf("expected the latest Update to win, got address %q", peers[0].Address)
+	}
+}
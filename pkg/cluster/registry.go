@@ -0,0 +1,72 @@
+package cluster
+
+import (
+	"sync"
+	"time"
+)
+
+// Role describes whether a control plane is the Global instance that owns
+// policy for a federation, or a Remote instance attached to it.
+type Role string
+
+const (
+	Global Role = "Global"
+	Remote Role = "Remote"
+)
+
+// Peer describes one control plane known to this instance, either because it
+// dialed in or because this instance dialed out to it.
+type Peer struct {
+	ClusterId string
+	Address   string
+	Role      Role
+	LastSeen  time.Time
+}
+
+// Registry tracks the other control planes taking part in a multi-zone /
+// federated Kuma deployment, so that the API server and kumactl can discover
+// the topology from any node.
+type Registry interface {
+	// Self returns the identity of this control plane.
+	Self() Peer
+	// Update records that a peer was seen just now, inserting it if new.
+	Update(peer Peer)
+	// Peers returns every peer known to this instance, excluding Self().
+	Peers() []Peer
+}
+
+// NewRegistry builds an in-memory Registry seeded with this instance's own
+// identity. Peers are expected to be kept fresh by gossiping over the
+// existing control-plane connection (see pkg/cluster/gossip.go).
+func NewRegistry(self Peer) Registry {
+	return &inMemoryRegistry{self: self, peers: map[string]Peer{}}
+}
+
+type inMemoryRegistry struct {
+	mu    sync.RWMutex
+	self  Peer
+	peers map[string]Peer
+}
+
+func (r *inMemoryRegistry) Self() Peer {
+	return r.self
+}
+
+func (r *inMemoryRegistry) Update(peer Peer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.peers[peer.ClusterId] = peer
+}
+
+func (r *inMemoryRegistry) Peers() []Peer {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	peers := make([]Peer, 0, len(r.peers))
+	for _, peer := range r.peers {
+		if peer.ClusterId == r.self.ClusterId {
+			continue
+		}
+		peers = append(peers, peer)
+	}
+	return peers
+}
@@ -0,0 +1,31 @@
+package mesh
+
+import (
+	"time"
+
+	"github.com/Kong/kuma/api/mesh/v1alpha1"
+)
+
+// ResourceMeta is the store-assigned identity carried alongside a Resource's
+// Spec: where it lives, and which revision of it this is.
+type ResourceMeta interface {
+	GetName() string
+	GetNamespace() string
+	GetMesh() string
+	GetVersion() string
+	GetCreationTime() time.Time
+	GetModificationTime() time.Time
+}
+
+// TrafficPermissionResource pairs a TrafficPermission's Spec with the Meta
+// the store attaches to it.
+type TrafficPermissionResource struct {
+	Meta ResourceMeta
+	Spec v1alpha1.TrafficPermission
+}
+
+// TrafficPermissionResourceList is a snapshot of every TrafficPermission
+// matched against a Dataplane, as handed to NetworkRBAC/HttpRBAC.
+type TrafficPermissionResourceList struct {
+	Items []*TrafficPermissionResource
+}
@@ -0,0 +1,186 @@
+package listeners
+
+import (
+	mesh_proto "github.com/Kong/kuma/api/mesh/v1alpha1"
+	mesh_core "github.com/Kong/kuma/pkg/core/resources/apis/mesh"
+	v2 "github.com/envoyproxy/go-control-plane/envoy/api/v2"
+	envoy_listener "github.com/envoyproxy/go-control-plane/envoy/api/v2/listener"
+	hrbac "github.com/envoyproxy/go-control-plane/envoy/config/filter/http/rbac/v2"
+	hcm "github.com/envoyproxy/go-control-plane/envoy/config/filter/network/http_connection_manager/v2"
+	rbac_config "github.com/envoyproxy/go-control-plane/envoy/config/rbac/v2"
+	envoy_matcher "github.com/envoyproxy/go-control-plane/envoy/type/matcher"
+	envoy_wellknown "github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes"
+)
+
+// HttpRBAC installs envoy.filters.http.rbac into the HTTP connection manager
+// of every filter chain, applying the same TrafficPermissions as NetworkRBAC
+// but also enforcing any HTTP-level match criteria (method, path, headers)
+// they carry. It is meant to be combined with NetworkRBAC on the same
+// inbound listener: NetworkRBAC scopes by destination port, HttpRBAC narrows
+// further within that port.
+func HttpRBAC(rbacEnabled bool, permissions *mesh_core.TrafficPermissionResourceList) ListenerBuilderOpt {
+	return ListenerBuilderOptFunc(func(config *ListenerBuilderConfig) {
+		if rbacEnabled {
+			config.Add(&HttpRBACConfigurer{permissions})
+		}
+	})
+}
+
+type HttpRBACConfigurer struct {
+	// Traffic Permissions to enforce.
+	permissions *mesh_core.TrafficPermissionResourceList
+}
+
+func (c *HttpRBACConfigurer) Configure(l *v2.Listener) error {
+	for i := range l.FilterChains {
+		for j, filter := range l.FilterChains[i].Filters {
+			if filter.Name != envoy_wellknown.HTTPConnectionManager {
+				continue
+			}
+			updated, err := insertHttpRbacFilter(filter, c.permissions)
+			if err != nil {
+				return err
+			}
+			l.FilterChains[i].Filters[j] = updated
+		}
+	}
+
+	return nil
+}
+
+// insertHttpRbacFilter unmarshals the HttpConnectionManager out of filter,
+// prepends an envoy.filters.http.rbac entry to its HttpFilters, and
+// re-marshals it back into a listener Filter.
+func insertHttpRbacFilter(filter *envoy_listener.Filter, permissions *mesh_core.TrafficPermissionResourceList) (*envoy_listener.Filter, error) {
+	typedConfig := filter.GetTypedConfig()
+	manager := &hcm.HttpConnectionManager{}
+	if err := ptypes.UnmarshalAny(typedConfig, manager); err != nil {
+		return nil, err
+	}
+
+	rbacFilter, err := createHttpRbacFilter(permissions)
+	if err != nil {
+		return nil, err
+	}
+	manager.HttpFilters = append([]*hcm.HttpFilter{rbacFilter}, manager.HttpFilters...)
+
+	managerMarshalled, err := ptypes.MarshalAny(manager)
+	if err != nil {
+		return nil, err
+	}
+	return &envoy_listener.Filter{
+		Name: envoy_wellknown.HTTPConnectionManager,
+		ConfigType: &envoy_listener.Filter_TypedConfig{
+			TypedConfig: managerMarshalled,
+		},
+	}, nil
+}
+
+func createHttpRbacFilter(permissions *mesh_core.TrafficPermissionResourceList) (*hcm.HttpFilter, error) {
+	rbacRule := createHttpRbacRule(permissions)
+	rbacMarshalled, err := ptypes.MarshalAny(rbacRule)
+	if err != nil {
+		return nil, err
+	}
+	return &hcm.HttpFilter{
+		Name: envoy_wellknown.HTTPRoleBasedAccessControl,
+		ConfigType: &hcm.HttpFilter_TypedConfig{
+			TypedConfig: rbacMarshalled,
+		},
+	}, nil
+}
+
+func createHttpRbacRule(permissions *mesh_core.TrafficPermissionResourceList) *hrbac.RBAC {
+	policies := make(map[string]*rbac_config.Policy, len(permissions.Items))
+	for _, permission := range permissions.Items {
+		policyName := permission.Meta.GetName()
+		policies[policyName] = createHttpPolicy(permission)
+	}
+
+	return &hrbac.RBAC{
+		Rules: &rbac_config.RBAC{
+			Action:   rbac_config.RBAC_ALLOW,
+			Policies: policies,
+		},
+	}
+}
+
+// createHttpPolicy reuses createPolicy's principal-building logic, but
+// replaces its port-scoped permission (meaningless at the HTTP layer) with
+// whatever HTTP match criteria the TrafficPermission carries, ANDed together.
+func createHttpPolicy(permission *mesh_core.TrafficPermissionResource) *rbac_config.Policy {
+	policy := createPolicy(0, permission)
+	policy.Permissions = []*rbac_config.Permission{httpMatchPermission(permission.Spec.Http)}
+	return policy
+}
+
+func httpMatchPermission(match *mesh_proto.TrafficPermission_Http) *rbac_config.Permission {
+	if match == nil {
+		return &rbac_config.Permission{Rule: &rbac_config.Permission_Any{Any: true}}
+	}
+
+	var rules []*rbac_config.Permission
+
+	if match.Method != "" {
+		rules = append(rules, &rbac_config.Permission{
+			Rule: &rbac_config.Permission_Header{
+				Header: &envoy_matcher.HeaderMatcher{
+					Name:                 ":method",
+					HeaderMatchSpecifier: &envoy_matcher.HeaderMatcher_ExactMatch{ExactMatch: match.Method},
+				},
+			},
+		})
+	}
+
+	switch {
+	case match.PathPrefix != "":
+		rules = append(rules, &rbac_config.Permission{
+			Rule: &rbac_config.Permission_UrlPath{
+				UrlPath: &envoy_matcher.PathMatcher{
+					Rule: &envoy_matcher.PathMatcher_Path{
+						Path: &envoy_matcher.StringMatcher{
+							MatchPattern: &envoy_matcher.StringMatcher_Prefix{Prefix: match.PathPrefix},
+						},
+					},
+				},
+			},
+		})
+	case match.PathRegex != "":
+		rules = append(rules, &rbac_config.Permission{
+			Rule: &rbac_config.Permission_UrlPath{
+				UrlPath: &envoy_matcher.PathMatcher{
+					Rule: &envoy_matcher.PathMatcher_Path{
+						Path: &envoy_matcher.StringMatcher{
+							MatchPattern: &envoy_matcher.StringMatcher_Regex{Regex: match.PathRegex},
+						},
+					},
+				},
+			},
+		})
+	}
+
+	for name, value := range match.Headers {
+		rules = append(rules, &rbac_config.Permission{
+			Rule: &rbac_config.Permission_Header{
+				Header: &envoy_matcher.HeaderMatcher{
+					Name:                 name,
+					HeaderMatchSpecifier: &envoy_matcher.HeaderMatcher_ExactMatch{ExactMatch: value},
+				},
+			},
+		})
+	}
+
+	switch len(rules) {
+	case 0:
+		return &rbac_config.Permission{Rule: &rbac_config.Permission_Any{Any: true}}
+	case 1:
+		return rules[0]
+	default:
+		return &rbac_config.Permission{
+			Rule: &rbac_config.Permission_AndRules{
+				AndRules: &rbac_config.Permission_Set{Rules: rules},
+			},
+		}
+	}
+}
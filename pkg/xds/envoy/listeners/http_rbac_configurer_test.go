@@ -0,0 +1,38 @@
+package listeners
+
+import (
+	"testing"
+
+	mesh_proto "github.com/Kong/kuma/api/mesh/v1alpha1"
+	rbac_config "github.com/envoyproxy/go-control-plane/envoy/config/rbac/v2"
+)
+
+// A TrafficPermission with no Http match criteria must not narrow the
+// permission at all - every HTTP request allowed at the L4 layer should
+// still be allowed here.
+func TestHttpMatchPermissionAllowsAnyWhenNoHttpSet(t *testing.T) {
+	permission := httpMatchPermission(nil)
+
+	if _, ok := permission.Rule.(*rbac_config.Permission_Any); !ok {
+		t.Fatalf("expected Permission_Any, got %T", permission.Rule)
+	}
+}
+
+// Method, path and headers must all be required at once (ANDed), so a
+// TrafficPermission scoped to "GET /foo" does not also allow "POST /foo" or
+// "GET /bar".
+func TestHttpMatchPermissionCombinesEveryCriterion(t *testing.T) {
+	permission := httpMatchPermission(&mesh_proto.TrafficPermission_Http{
+		Method:     "GET",
+		PathPrefix: "/foo",
+		Headers:    map[string]string{"x-env": "prod"},
+	})
+
+	andRules, ok := permission.Rule.(*rbac_config.Permission_AndRules)
+	if !ok {
+		t.Fatalf("expected Permission_AndRules, got %T", permission.Rule)
+	}
+	if len(andRules.AndRules.Rules) != 3 {
+		t.Fatalf("expected 3 ANDed rules (method, path, header), got %d", len(andRules.AndRules.Rules))
+	}
+}
@@ -16,22 +16,27 @@ import (
 	envoy_matcher "github.com/envoyproxy/go-control-plane/envoy/type/matcher"
 )
 
-func NetworkRBAC(rbacEnabled bool, permissions *mesh_core.TrafficPermissionResourceList) ListenerBuilderOpt {
+// NetworkRBAC builds a L4 RBAC filter scoped to destinationPort, so that a
+// TrafficPermission targeting one service on a dataplane does not implicitly
+// open every other inbound port of that dataplane.
+func NetworkRBAC(rbacEnabled bool, destinationPort uint32, permissions *mesh_core.TrafficPermissionResourceList) ListenerBuilderOpt {
 	return ListenerBuilderOptFunc(func(config *ListenerBuilderConfig) {
 		if rbacEnabled {
-			config.Add(&NetworkRBACConfigurer{permissions})
+			config.Add(&NetworkRBACConfigurer{destinationPort, permissions})
 		}
 	})
 }
 
 type NetworkRBACConfigurer struct {
+	// Destination port of the inbound listener this filter is attached to.
+	destinationPort uint32
 	// Traffic Permissions to enforce.
 	permissions *mesh_core.TrafficPermissionResourceList
 }
 
 func (c *NetworkRBACConfigurer) Configure(l *v2.Listener) error {
 	for i := range l.FilterChains {
-		filter, err := createRbacFilter(l.Name, c.permissions)
+		filter, err := createRbacFilter(l.Name, c.destinationPort, c.permissions)
 		if err != nil {
 			return err
 		}
@@ -43,8 +48,8 @@ func (c *NetworkRBACConfigurer) Configure(l *v2.Listener) error {
 	return nil
 }
 
-func createRbacFilter(listenerName string, permissions *mesh_core.TrafficPermissionResourceList) (*envoy_listener.Filter, error) {
-	rbacRule := createRbacRule(listenerName, permissions)
+func createRbacFilter(listenerName string, destinationPort uint32, permissions *mesh_core.TrafficPermissionResourceList) (*envoy_listener.Filter, error) {
+	rbacRule := createRbacRule(listenerName, destinationPort, permissions)
 	rbacMarshalled, err := ptypes.MarshalAny(rbacRule)
 	if err != nil {
 		return nil, err
@@ -57,11 +62,11 @@ func createRbacFilter(listenerName string, permissions *mesh_core.TrafficPermiss
 	}, nil
 }
 
-func createRbacRule(listenerName string, permissions *mesh_core.TrafficPermissionResourceList) *rbac.RBAC {
+func createRbacRule(listenerName string, destinationPort uint32, permissions *mesh_core.TrafficPermissionResourceList) *rbac.RBAC {
 	policies := make(map[string]*rbac_config.Policy, len(permissions.Items))
 	for _, permission := range permissions.Items {
 		policyName := permission.Meta.GetName()
-		policies[policyName] = createPolicy(permission)
+		policies[policyName] = createPolicy(destinationPort, permission)
 	}
 
 	return &rbac.RBAC{
@@ -73,7 +78,7 @@ func createRbacRule(listenerName string, permissions *mesh_core.TrafficPermissio
 	}
 }
 
-func createPolicy(permission *mesh_core.TrafficPermissionResource) *rbac_config.Policy {
+func createPolicy(destinationPort uint32, permission *mesh_core.TrafficPermissionResource) *rbac_config.Policy {
 	principals := []*rbac_config.Principal{}
 	// build principals list: one per sources/destinations rule
 	for _, source := range permission.Spec.Sources {
@@ -100,13 +105,13 @@ func createPolicy(permission *mesh_core.TrafficPermissionResource) *rbac_config.
 	return &rbac_config.Policy{
 		Permissions: []*rbac_config.Permission{
 			{
-				Rule: &rbac_config.Permission_Any{
-					// todo(jakubdyszkiewicz) for now it matches on any destination port, which means that
-					// if dataplane has two services ex. web, web-api. Allowing traffic on web will also work on web-api
-					Any: true,
+				// scope the permission to the inbound listener's destination port, so that
+				// a permission on service "web" does not also allow "web-api" on the same dataplane
+				Rule: &rbac_config.Permission_DestinationPort{
+					DestinationPort: destinationPort,
 				},
 			},
 		},
 		Principals: principals,
 	}
-}
\ No newline at end of file
+}
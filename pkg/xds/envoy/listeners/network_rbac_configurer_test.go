@@ -0,0 +1,62 @@
+package listeners
+
+import (
+	"testing"
+	"time"
+
+	mesh_proto "github.com/Kong/kuma/api/mesh/v1alpha1"
+	mesh_core "github.com/Kong/kuma/pkg/core/resources/apis/mesh"
+	rbac_config "github.com/envoyproxy/go-control-plane/envoy/config/rbac/v2"
+)
+
+// testResourceMeta is a minimal mesh_core.ResourceMeta stand-in, just enough to
+// exercise createPolicy without pulling in a real store.
+type testResourceMeta struct {
+	name string
+	mesh string
+}
+
+func (m *testResourceMeta) GetName() string                { return m.name }
+func (m *testResourceMeta) GetNamespace() string           { return m.mesh }
+func (m *testResourceMeta) GetMesh() string                { return m.mesh }
+func (m *testResourceMeta) GetVersion() string             { return "1" }
+func (m *testResourceMeta) GetCreationTime() time.Time     { return time.Time{} }
+func (m *testResourceMeta) GetModificationTime() time.Time { return time.Time{} }
+
+func trafficPermission(name string, service string) *mesh_core.TrafficPermissionResource {
+	return &mesh_core.TrafficPermissionResource{
+		Meta: &testResourceMeta{name: name, mesh: "default"},
+		Spec: mesh_proto.TrafficPermission{
+			Sources: []*mesh_proto.TrafficPermission_Source{
+				{Match: map[string]string{"service": service}},
+			},
+		},
+	}
+}
+
+// A two-service dataplane (e.g. "web" on 8080 and "web-api" on 8081) must
+// enforce distinct RBAC permissions per inbound port: a TrafficPermission
+// targeting "web" should not implicitly open "web-api".
+func TestCreatePolicyIsScopedToDestinationPort(t *testing.T) {
+	permission := trafficPermission("web-permission", "web-client")
+
+	webPolicy := createPolicy(8080, permission)
+	webApiPolicy := createPolicy(8081, permission)
+
+	assertDestinationPort(t, webPolicy, 8080)
+	assertDestinationPort(t, webApiPolicy, 8081)
+}
+
+func assertDestinationPort(t *testing.T, policy *rbac_config.Policy, expected uint32) {
+	t.Helper()
+	if len(policy.Permissions) != 1 {
+		t.Fatalf("expected exactly one Permission, got %d", len(policy.Permissions))
+	}
+	rule, ok := policy.Permissions[0].Rule.(*rbac_config.Permission_DestinationPort)
+	if !ok {
+		t.Fatalf("expected Permission_DestinationPort, got %T", policy.Permissions[0].Rule)
+	}
+	if rule.DestinationPort != expected {
+		t.Errorf("expected destination port %d, got %d", expected, rule.DestinationPort)
+	}
+}